@@ -0,0 +1,198 @@
+package quick
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"html/template"
+	"strconv"
+	"strings"
+)
+
+const (
+	ContentTypeTextPlain = `text/plain`
+	ContentTypeTextHTML  = `text/html`
+)
+
+// shortTypes lets Accepts take "json" for application/json and so on.
+var shortTypes = map[string]string{
+	"json": ContentTypeAppJSON,
+	"xml":  ContentTypeAppXML,
+	"html": ContentTypeTextHTML,
+	"text": ContentTypeTextPlain,
+	"txt":  ContentTypeTextPlain,
+}
+
+// Accepts returns the offer the Accept header of the request prefers, or
+// "" when it accepts none of them. Offers are media types like
+// "application/json" or the short names json, xml, html and text, and are
+// returned as given. q-values rank the header entries, a more specific
+// entry wins over a wildcard and ties go to the first offer. A request
+// without Accept gets the first offer.
+func (c *Ctx) Accepts(offers ...string) string {
+	return negotiate(c.Request.Header.Get("Accept"), offers, matchMediaType)
+}
+
+// AcceptsEncodings returns the offer the Accept-Encoding header of the
+// request prefers, e.g. "br" or "gzip", or "" when none is accepted.
+func (c *Ctx) AcceptsEncodings(offers ...string) string {
+	return negotiate(c.Request.Header.Get("Accept-Encoding"), offers, matchToken)
+}
+
+// NegotiateEncoding returns the offer an Accept-Encoding header prefers,
+// or "" when it accepts none of them. It ranks entries like
+// AcceptsEncodings, but an empty header only accepts identity and gets
+// "", so middlewares can pick a Content-Encoding with it.
+func NegotiateEncoding(header string, offers ...string) string {
+	if len(header) == 0 {
+		return ""
+	}
+	return negotiate(header, offers, matchToken)
+}
+
+// AcceptsLanguages returns the offer the Accept-Language header of the
+// request prefers, or "" when none is accepted. A range like "en" matches
+// the offers "en" and "en-US".
+func (c *Ctx) AcceptsLanguages(offers ...string) string {
+	return negotiate(c.Request.Header.Get("Accept-Language"), offers, matchLanguage)
+}
+
+// Format writes v as JSON, XML, plain text or HTML, whichever the Accept
+// header prefers, JSON when the request has none. XML is skipped for
+// values it can not encode, like maps, in favor of the next acceptable
+// type. Text and HTML are the fmt.Sprint of v, escaped for HTML unless v
+// is a template.HTML. Requests that accept none of them are answered
+// with 406.
+func (c *Ctx) Format(v any) error {
+	c.Append("Vary", "Accept")
+
+	var xmlBody bytes.Buffer
+	mediaType := c.Accepts(ContentTypeAppJSON, ContentTypeAppXML, ContentTypeTextXML, ContentTypeTextPlain, ContentTypeTextHTML)
+	if mediaType == ContentTypeAppXML || mediaType == ContentTypeTextXML {
+		if err := c.codec(ContentTypeTextXML).Encode(&xmlBody, v); err != nil {
+			mediaType = c.Accepts(ContentTypeAppJSON, ContentTypeTextPlain, ContentTypeTextHTML)
+		}
+	}
+
+	switch mediaType {
+	case ContentTypeAppJSON:
+		return c.JSON(v)
+	case ContentTypeAppXML, ContentTypeTextXML:
+		c.Set("Content-Type", ContentTypeTextXML)
+		return c.writeResponse(xmlBody.Bytes())
+	case ContentTypeTextPlain:
+		c.Set("Content-Type", "text/plain; charset=utf-8")
+		return c.SendString(fmt.Sprint(v))
+	case ContentTypeTextHTML:
+		c.Set("Content-Type", "text/html; charset=utf-8")
+		if s, ok := v.(template.HTML); ok {
+			return c.SendString(string(s))
+		}
+		return c.SendString(html.EscapeString(fmt.Sprint(v)))
+	}
+	return ErrNotAcceptable
+}
+
+// acceptRange is one entry of an Accept-* header.
+type acceptRange struct {
+	value string
+	q     float64
+}
+
+func parseAccept(header string) []acceptRange {
+	var ranges []acceptRange
+	for _, part := range strings.Split(header, ",") {
+		value, params, _ := strings.Cut(part, ";")
+		value = strings.ToLower(strings.TrimSpace(value))
+		if len(value) == 0 {
+			continue
+		}
+
+		q := 1.0
+		for _, param := range strings.Split(params, ";") {
+			name, val, _ := strings.Cut(param, "=")
+			if strings.TrimSpace(name) == "q" {
+				if f, err := strconv.ParseFloat(strings.TrimSpace(val), 64); err == nil && f >= 0 && f <= 1 {
+					q = f
+				}
+			}
+		}
+		ranges = append(ranges, acceptRange{value: value, q: q})
+	}
+	return ranges
+}
+
+// negotiate picks the offer with the highest q in header. For each offer
+// the most specific matching range decides its q; match scores how
+// specific a range is for an offer, 0 meaning it does not match.
+func negotiate(header string, offers []string, match func(rng, offer string) int) string {
+	if len(offers) == 0 {
+		return ""
+	}
+	ranges := parseAccept(header)
+	if len(ranges) == 0 {
+		return offers[0]
+	}
+
+	best, bestQ, bestScore := "", 0.0, 0
+	for _, offer := range offers {
+		q, score := 0.0, 0
+		for _, rng := range ranges {
+			if s := match(rng.value, offer); s > score {
+				q, score = rng.q, s
+			}
+		}
+		if q > bestQ || (q == bestQ && q > 0 && score > bestScore) {
+			best, bestQ, bestScore = offer, q, score
+		}
+	}
+	return best
+}
+
+// matchMediaType scores type/subtype 3, type/* 2 and */* 1.
+func matchMediaType(rng, offer string) int {
+	if t, ok := shortTypes[offer]; ok {
+		offer = t
+	}
+	offer, _, _ = strings.Cut(strings.ToLower(offer), ";")
+	offerType, offerSub, _ := strings.Cut(strings.TrimSpace(offer), "/")
+	rngType, rngSub, _ := strings.Cut(rng, "/")
+
+	switch {
+	case rngType == "*" && rngSub == "*":
+		return 1
+	case rngType != offerType:
+		return 0
+	case rngSub == "*":
+		return 2
+	case rngSub == offerSub:
+		return 3
+	}
+	return 0
+}
+
+// matchToken scores an exact match 2 and * 1.
+func matchToken(rng, offer string) int {
+	switch {
+	case strings.EqualFold(rng, offer):
+		return 2
+	case rng == "*":
+		return 1
+	}
+	return 0
+}
+
+// matchLanguage scores an exact match above a prefix range like "en" for
+// "en-US", which scores above *.
+func matchLanguage(rng, offer string) int {
+	offer = strings.ToLower(offer)
+	switch {
+	case rng == offer:
+		return 3
+	case strings.HasPrefix(offer, rng) && offer[len(rng)] == '-':
+		return 2
+	case rng == "*":
+		return 1
+	}
+	return 0
+}
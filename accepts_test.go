@@ -0,0 +1,126 @@
+package quick
+
+import (
+	"html/template"
+	"net/http/httptest"
+	"testing"
+)
+
+// cover     -> go test -v -count=1 -cover -failfast -run ^TestCtx_AcceptsEncodings$
+func TestCtx_AcceptsEncodings(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{header: "", want: "br"},
+		{header: "gzip, deflate", want: "gzip"},
+		{header: "br;q=0.5, gzip", want: "gzip"},
+		{header: "*", want: "br"},
+		{header: "*, br;q=0", want: "gzip"},
+		{header: "identity", want: ""},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set("Accept-Encoding", tt.header)
+		c := &Ctx{Request: req}
+		if got := c.AcceptsEncodings("br", "gzip"); got != tt.want {
+			t.Errorf("%q: was suppose to return %q and %q come", tt.header, tt.want, got)
+		}
+	}
+}
+
+// cover     -> go test -v -count=1 -cover -failfast -run ^TestNegotiateEncoding$
+func TestNegotiateEncoding(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{header: "", want: ""},
+		{header: "GZIP", want: "gzip"},
+		{header: "gzip;foo=1;q=0, br;q=0.1", want: "br"},
+		{header: "gzip;q=0", want: ""},
+		{header: "*;q=0.5, gzip", want: "gzip"},
+	}
+	for _, tt := range tests {
+		if got := NegotiateEncoding(tt.header, "br", "gzip"); got != tt.want {
+			t.Errorf("%q: was suppose to return %q and %q come", tt.header, tt.want, got)
+		}
+	}
+}
+
+// cover     -> go test -v -count=1 -cover -failfast -run ^TestCtx_AcceptsLanguages$
+func TestCtx_AcceptsLanguages(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{header: "pt-BR, en;q=0.8", want: "pt-BR"},
+		{header: "en", want: "en-US"},
+		{header: "es, *;q=0.1", want: "en-US"},
+		{header: "fr", want: ""},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set("Accept-Language", tt.header)
+		c := &Ctx{Request: req}
+		if got := c.AcceptsLanguages("en-US", "pt-BR"); got != tt.want {
+			t.Errorf("%q: was suppose to return %q and %q come", tt.header, tt.want, got)
+		}
+	}
+}
+
+// cover     -> go test -v -count=1 -cover -failfast -run ^TestCtx_Format$
+// coverHTML -> go test -v -count=1 -failfast -cover -coverprofile=coverage.out -run ^TestCtx_Format$; go tool cover -html=coverage.out
+func TestCtx_Format(t *testing.T) {
+	type user struct {
+		Name string `json:"name" xml:"name"`
+	}
+
+	q := New()
+	q.Get("/user", func(c *Ctx) error {
+		return c.Format(user{Name: "jeff"})
+	})
+	q.Get("/html", func(c *Ctx) error {
+		if c.Query["raw"] == "1" {
+			return c.Format(template.HTML("<b>jeff</b>"))
+		}
+		return c.Format("<b>jeff</b>")
+	})
+	q.Get("/map", func(c *Ctx) error {
+		return c.Format(map[string]string{"name": "jeff"})
+	})
+
+	tests := []struct {
+		uri    string
+		accept string
+		code   int
+		ctype  string
+		body   string
+	}{
+		{uri: "/user", accept: "", code: 200, ctype: ContentTypeAppJSON, body: `{"name":"jeff"}`},
+		{uri: "/user", accept: "application/json", code: 200, ctype: ContentTypeAppJSON, body: `{"name":"jeff"}`},
+		{uri: "/user", accept: "application/xml", code: 200, ctype: ContentTypeTextXML, body: `<user><name>jeff</name></user>`},
+		{uri: "/user", accept: "text/plain", code: 200, ctype: "text/plain; charset=utf-8", body: `{jeff}`},
+		{uri: "/html", accept: "text/html", code: 200, ctype: "text/html; charset=utf-8", body: `&lt;b&gt;jeff&lt;/b&gt;`},
+		{uri: "/html?raw=1", accept: "text/html", code: 200, ctype: "text/html; charset=utf-8", body: `<b>jeff</b>`},
+		{uri: "/map", accept: "application/xml, application/json;q=0.9", code: 200, ctype: ContentTypeAppJSON, body: `{"name":"jeff"}`},
+		{uri: "/map", accept: "text/xml, text/plain;q=0.5", code: 200, ctype: "text/plain; charset=utf-8", body: `map[name:jeff]`},
+		{uri: "/map", accept: "application/xml", code: 406, ctype: ContentTypeTextXML, body: `<error><code>406</code><message>Not Acceptable</message></error>`},
+		{uri: "/user", accept: "image/png", code: 406, ctype: "text/plain; charset=utf-8", body: `Not Acceptable`},
+	}
+	for _, tt := range tests {
+		data, err := q.QuickTest("GET", tt.uri, map[string]string{"Accept": tt.accept})
+		if err != nil {
+			t.Fatalf("error: %v", err)
+		}
+		if data.StatusCode() != tt.code || data.BodyStr() != tt.body {
+			t.Errorf("%s %q: was suppose to return %d %s and %d %s come", tt.uri, tt.accept, tt.code, tt.body, data.StatusCode(), data.BodyStr())
+		}
+		if ct := data.Response().Header.Get("Content-Type"); ct != tt.ctype {
+			t.Errorf("%s %q: was suppose to return Content-Type %s and %s come", tt.uri, tt.accept, tt.ctype, ct)
+		}
+		if vary := data.Response().Header.Get("Vary"); vary != "Accept" {
+			t.Errorf("%s %q: was suppose to return Vary Accept and %q come", tt.uri, tt.accept, vary)
+		}
+	}
+}
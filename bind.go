@@ -0,0 +1,314 @@
+package quick
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jeffotoni/quick/internal/concat"
+)
+
+var (
+	fileHeaderType  = reflect.TypeOf((*multipart.FileHeader)(nil))
+	fileHeadersType = reflect.TypeOf([]*multipart.FileHeader(nil))
+	durationType    = reflect.TypeOf(time.Duration(0))
+)
+
+// FieldError is a request value that could not be converted to the type
+// of its struct field.
+type FieldError struct {
+	Field   string `json:"field" xml:"field"`
+	Source  string `json:"source" xml:"source"`
+	Key     string `json:"key" xml:"key"`
+	Value   string `json:"value" xml:"value"`
+	Message string `json:"message" xml:"message"`
+	// Rule is the validate rule that failed, empty for conversion errors.
+	Rule string `json:"rule,omitempty" xml:"rule,omitempty"`
+	Err  error  `json:"-" xml:"-"`
+}
+
+func (e *FieldError) Error() string {
+	return concat.String(e.Source, " ", strconv.Quote(e.Key), ": ", e.Message)
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// BindErrors lists every field a bind could not fill.
+type BindErrors []*FieldError
+
+func (e BindErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, fe := range e {
+		msgs[i] = fe.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// BindParams fills v from the route params using param tags, e.g.
+// `param:"id"` for the :id segment. Conversion failures are answered
+// with 400 and the list of fields.
+func (c *Ctx) BindParams(v any) error {
+	return bindError(bindValues(v, "param", func(name string) []string {
+		if val, ok := c.Params[name]; ok {
+			return []string{val}
+		}
+		return nil
+	}, nil))
+}
+
+// BindQuery fills v from the query string using query tags. Slice fields
+// take every value of a repeated key.
+func (c *Ctx) BindQuery(v any) error {
+	return bindError(bindValues(v, "query", valuesOf(c.Request.URL.Query()), nil))
+}
+
+// BindHeaders fills v from the request headers using header tags,
+// matched case-insensitively.
+func (c *Ctx) BindHeaders(v any) error {
+	return bindError(bindValues(v, "header", c.Request.Header.Values, nil))
+}
+
+// BindAll fills v from the params, the query string, the headers and the
+// body, in that order, reporting the conversion failures of all of them,
+// and then validates it.
+func (c *Ctx) BindAll(v any) error {
+	var errs BindErrors
+	collect := func(err error) error {
+		var fieldErrs BindErrors
+		if errors.As(err, &fieldErrs) {
+			errs = append(errs, fieldErrs...)
+			return nil
+		}
+		return err
+	}
+
+	if err := collect(c.BindParams(v)); err != nil {
+		return err
+	}
+	if err := collect(c.BindQuery(v)); err != nil {
+		return err
+	}
+	if err := collect(c.BindHeaders(v)); err != nil {
+		return err
+	}
+	if err := collect(extractBind(c, v)); err != nil {
+		return err
+	}
+
+	if len(errs) > 0 {
+		return bindError(errs)
+	}
+	return c.Validate(v)
+}
+
+// bindError answers conversion errors with 400 listing the fields.
+func bindError(err error) error {
+	var errs BindErrors
+	if errors.As(err, &errs) {
+		return ErrBadRequest.Wrap(err).WithDetails(errs)
+	}
+	return err
+}
+
+// bindValues fills the struct v points to from the values get returns for
+// the name in each field's tag. Fields without the tag are left alone.
+// Fields of type *multipart.FileHeader and []*multipart.FileHeader are
+// filled from files. Every failed conversion is reported in BindErrors.
+func bindValues(v any, tag string, get func(string) []string, files map[string][]*multipart.FileHeader) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return errors.New("quick: bind needs a non-nil pointer to a struct")
+	}
+
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			rv.Set(reflect.New(rv.Type().Elem()))
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return fmt.Errorf("quick: bind needs a pointer to a struct, not %s", rv.Type())
+	}
+
+	var errs BindErrors
+	bindStruct(rv, tag, get, files, &errs)
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func bindStruct(rv reflect.Value, tag string, get func(string) []string, files map[string][]*multipart.FileHeader, errs *BindErrors) {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		fv := rv.Field(i)
+
+		name := field.Tag.Get(tag)
+		if name == "-" {
+			continue
+		}
+
+		// embedded structs are walked even when their type is unexported
+		if field.Anonymous && len(name) == 0 {
+			ft := field.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct && ft != reflect.TypeOf(time.Time{}) {
+				if fv.Kind() == reflect.Ptr {
+					if fv.IsNil() {
+						if !fv.CanSet() {
+							continue
+						}
+						fv.Set(reflect.New(ft))
+					}
+					fv = fv.Elem()
+				}
+				bindStruct(fv, tag, get, files, errs)
+				continue
+			}
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+
+		if len(name) == 0 {
+			continue
+		}
+
+		switch field.Type {
+		case fileHeaderType:
+			if fhs := files[name]; len(fhs) > 0 {
+				fv.Set(reflect.ValueOf(fhs[0]))
+			}
+			continue
+		case fileHeadersType:
+			if fhs := files[name]; len(fhs) > 0 {
+				fv.Set(reflect.ValueOf(fhs))
+			}
+			continue
+		}
+
+		vals := get(name)
+		if len(vals) == 0 {
+			continue
+		}
+		if err := setField(fv, vals); err != nil {
+			*errs = append(*errs, &FieldError{
+				Field:   field.Name,
+				Source:  tag,
+				Key:     name,
+				Value:   strings.Join(vals, ","),
+				Message: fieldErrorMessage(fv, err),
+				Err:     err,
+			})
+		}
+	}
+}
+
+// fieldErrorMessage hides the strconv details from the client.
+func fieldErrorMessage(fv reflect.Value, err error) string {
+	var numErr *strconv.NumError
+	if errors.As(err, &numErr) {
+		if errors.Is(numErr.Err, strconv.ErrRange) {
+			return concat.String("value out of range for ", fv.Type().String())
+		}
+		return concat.String("invalid value for ", fv.Type().String())
+	}
+	return err.Error()
+}
+
+// setField sets fv from vals: slices take every value, any other type
+// the first one.
+func setField(fv reflect.Value, vals []string) error {
+	if fv.Kind() == reflect.Slice && !isTextUnmarshaler(fv) {
+		s := reflect.MakeSlice(fv.Type(), len(vals), len(vals))
+		for i, val := range vals {
+			if err := setValue(s.Index(i), val); err != nil {
+				return err
+			}
+		}
+		fv.Set(s)
+		return nil
+	}
+	return setValue(fv, vals[0])
+}
+
+func isTextUnmarshaler(fv reflect.Value) bool {
+	return fv.CanAddr() && fv.Addr().Type().Implements(reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem())
+}
+
+// setValue converts s to the type of fv. Empty values leave non-string
+// fields untouched.
+func setValue(fv reflect.Value, s string) error {
+	if fv.Kind() == reflect.Ptr {
+		if len(s) == 0 && fv.Type().Elem().Kind() != reflect.String {
+			return nil
+		}
+		if fv.IsNil() {
+			fv.Set(reflect.New(fv.Type().Elem()))
+		}
+		return setValue(fv.Elem(), s)
+	}
+
+	if isTextUnmarshaler(fv) {
+		if len(s) == 0 {
+			return nil
+		}
+		// time.Time takes RFC 3339 through UnmarshalText
+		return fv.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s))
+	}
+
+	if len(s) == 0 && fv.Kind() != reflect.String {
+		return nil
+	}
+
+	switch fv.Kind() {
+	case reflect.String:
+		fv.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		fv.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if fv.Type() == durationType {
+			d, err := time.ParseDuration(s)
+			if err != nil {
+				return err
+			}
+			fv.SetInt(int64(d))
+			return nil
+		}
+		n, err := strconv.ParseInt(s, 10, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported type %s", fv.Type())
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package quick
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+type bindPage struct {
+	Page  int      `query:"page"`
+	Sort  []string `query:"sort"`
+	Debug *bool    `query:"debug"`
+}
+
+type bindRequest struct {
+	bindPage
+	ID       uint64        `param:"id"`
+	Tenant   string        `header:"X-Tenant"`
+	Since    time.Time     `query:"since"`
+	Timeout  time.Duration `query:"timeout"`
+	Score    *float64      `query:"score"`
+	Name     string        `json:"name"`
+	Internal string        `query:"-"`
+}
+
+// cover     -> go test -v -count=1 -cover -failfast -run ^TestCtx_BindAll$
+// coverHTML -> go test -v -count=1 -failfast -cover -coverprofile=coverage.out -run ^TestCtx_BindAll$; go tool cover -html=coverage.out
+func TestCtx_BindAll(t *testing.T) {
+	var got bindRequest
+	q := New()
+	q.Put("/v1/user/:id", func(c *Ctx) error {
+		got = bindRequest{}
+		return c.BindAll(&got)
+	})
+
+	uri := "/v1/user/42?page=2&sort=name&sort=-age&debug=true&since=2024-01-02T03:04:05Z&timeout=1m30s&score=9.5&Internal=x"
+	headers := map[string]string{"x-tenant": "acme", "Content-Type": "application/json"}
+	data, err := q.QuickTest("PUT", uri, headers, []byte(`{"name":"jeff"}`))
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	if data.StatusCode() != 200 {
+		t.Fatalf("was suppose to return 200 and %d %s come", data.StatusCode(), data.BodyStr())
+	}
+
+	since := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	if got.ID != 42 || got.Tenant != "acme" || got.Page != 2 || len(got.Sort) != 2 || got.Sort[1] != "-age" ||
+		got.Debug == nil || !*got.Debug || !got.Since.Equal(since) || got.Timeout != 90*time.Second ||
+		got.Score == nil || *got.Score != 9.5 || got.Name != "jeff" || len(got.Internal) > 0 {
+		t.Errorf("was suppose to bind every source and %+v come", got)
+	}
+}
+
+func TestCtx_BindFieldErrors(t *testing.T) {
+	q := New()
+	q.Get("/v1/user/:id", func(c *Ctx) error {
+		var req bindRequest
+		return c.BindAll(&req)
+	})
+	q.Get("/v1/page", func(c *Ctx) error {
+		var page bindPage
+		err := c.BindQuery(&page)
+
+		var errs BindErrors
+		if !errors.As(err, &errs) || !errors.Is(err, ErrBadRequest) {
+			t.Errorf("was suppose to return BindErrors wrapped in ErrBadRequest and %v come", err)
+		}
+		return err
+	})
+
+	data, _ := q.QuickTest("GET", "/v1/user/abc?page=x&debug=maybe", map[string]string{"Accept": "application/json"})
+	if data.StatusCode() != 400 {
+		t.Fatalf("was suppose to return 400 and %d come", data.StatusCode())
+	}
+
+	var body struct {
+		Details []FieldError `json:"details"`
+	}
+	if err := json.Unmarshal(data.Body(), &body); err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	want := []FieldError{
+		{Field: "ID", Source: "param", Key: "id", Value: "abc", Message: "invalid value for uint64"},
+		{Field: "Page", Source: "query", Key: "page", Value: "x", Message: "invalid value for int"},
+		{Field: "Debug", Source: "query", Key: "debug", Value: "maybe", Message: "invalid value for *bool"},
+	}
+	if len(body.Details) != len(want) {
+		t.Fatalf("was suppose to list %d fields and %+v come", len(want), body.Details)
+	}
+	for i := range want {
+		if body.Details[i] != want[i] {
+			t.Errorf("was suppose to report %+v and %+v come", want[i], body.Details[i])
+		}
+	}
+
+	// the field list is sent as JSON unless the client asks for text
+	data, _ = q.QuickTest("GET", "/v1/page?page=99999999999999999999", nil)
+	wantBody := `{"code":400,"message":"Bad Request","details":[{"field":"Page","source":"query","key":"page","value":"99999999999999999999","message":"value out of range for int"}]}`
+	if data.StatusCode() != 400 || data.BodyStr() != wantBody {
+		t.Errorf("was suppose to return 400 %s and %d %s come", wantBody, data.StatusCode(), data.BodyStr())
+	}
+	data, _ = q.QuickTest("GET", "/v1/page?page=99999999999999999999", map[string]string{"Accept": "text/plain"})
+	if data.StatusCode() != 400 || data.BodyStr() != "Bad Request" {
+		t.Errorf("was suppose to return 400 Bad Request and %d %s come", data.StatusCode(), data.BodyStr())
+	}
+}
@@ -0,0 +1,200 @@
+package quick
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"strings"
+
+	"github.com/jeffotoni/quick/internal/concat"
+	"google.golang.org/protobuf/proto"
+)
+
+// Codec decodes request bodies and encodes responses of a media type.
+// Register one with Quick.RegisterCodec to plug in msgpack, CBOR, YAML or
+// another JSON library.
+type Codec interface {
+	Decode(r io.Reader, v any) error
+	Encode(w io.Writer, v any) error
+}
+
+// jsonCodec uses protojson for proto.Message values, so a handler that
+// binds and encodes protobuf messages serves JSON clients too.
+type jsonCodec struct{}
+
+func (jsonCodec) Decode(r io.Reader, v any) error {
+	if m, ok := v.(proto.Message); ok {
+		return decodeProtoJSON(r, m)
+	}
+	return json.NewDecoder(r).Decode(v)
+}
+
+// Encode uses json.Marshal, json.Encoder would add a newline.
+func (jsonCodec) Encode(w io.Writer, v any) error {
+	if m, ok := v.(proto.Message); ok {
+		return encodeProtoJSON(w, m)
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(b)
+	return err
+}
+
+type xmlCodec struct{}
+
+func (xmlCodec) Decode(r io.Reader, v any) error {
+	return xml.NewDecoder(r).Decode(v)
+}
+
+func (xmlCodec) Encode(w io.Writer, v any) error {
+	return xml.NewEncoder(w).Encode(v)
+}
+
+// defaultCodecs are used for media types no registered codec replaces.
+var (
+	defaultCodecs = map[string]Codec{
+		ContentTypeAppJSON:     jsonCodec{},
+		ContentTypeAppXML:      xmlCodec{},
+		ContentTypeTextXML:     xmlCodec{},
+		ContentTypeProtoBuf:    protoCodec{},
+		ContentTypeAppProtoBuf: protoCodec{},
+	}
+	defaultCodecTypes = []string{ContentTypeAppJSON, ContentTypeAppXML, ContentTypeTextXML, ContentTypeProtoBuf, ContentTypeAppProtoBuf}
+)
+
+// RegisterCodec sets the codec Bind and Encode use for mediaType, e.g.
+// "application/msgpack". It replaces the built-in codec of
+// application/json, application/xml, text/xml or application/x-protobuf,
+// which Ctx.JSON, Ctx.XML and Ctx.ProtoBuf use too. It must be called before the server starts.
+func (q *Quick) RegisterCodec(mediaType string, codec Codec) {
+	mediaType = strings.ToLower(mediaType)
+	if q.codecs == nil {
+		q.codecs = make(map[string]Codec)
+	}
+	if _, ok := q.codecs[mediaType]; !ok {
+		q.codecTypes = append(q.codecTypes, mediaType)
+	}
+	q.codecs[mediaType] = codec
+}
+
+// codec returns the codec of mediaType. Structured syntax types like
+// application/problem+json fall back to the codec of application/json.
+func (c *Ctx) codec(mediaType string) Codec {
+	mediaType = strings.ToLower(mediaType)
+	lookup := func(mt string) Codec {
+		if c.quick != nil {
+			if codec, ok := c.quick.codecs[mt]; ok {
+				return codec
+			}
+		}
+		return defaultCodecs[mt]
+	}
+
+	if codec := lookup(mediaType); codec != nil {
+		return codec
+	}
+	if i := strings.LastIndexByte(mediaType, '+'); i >= 0 {
+		return lookup(concat.String("application/", mediaType[i+1:]))
+	}
+	return nil
+}
+
+// codecTypes lists the media types Encode can answer v with, the
+// built-in ones first. The built-in protobuf codec is only offered for
+// proto.Message values.
+func (c *Ctx) codecTypes(v any) []string {
+	types := defaultCodecTypes
+	if c.quick != nil && len(c.quick.codecTypes) > 0 {
+		types = append([]string(nil), defaultCodecTypes...)
+		for _, mt := range c.quick.codecTypes {
+			if _, ok := defaultCodecs[mt]; !ok {
+				types = append(types, mt)
+			}
+		}
+	}
+
+	if _, ok := v.(proto.Message); ok {
+		return types
+	}
+	offers := make([]string, 0, len(types))
+	for _, mt := range types {
+		if _, ok := c.codec(mt).(protoCodec); !ok {
+			offers = append(offers, mt)
+		}
+	}
+	return offers
+}
+
+// Encode writes v with the codec of the response Content-Type when the
+// handler set one, or else of the registered media type the Accept header
+// prefers, JSON for requests without Accept. A type whose codec can not
+// encode v, like XML for a map, gives way to the next acceptable one.
+// Requests that accept none of them are answered with 406.
+func (c *Ctx) Encode(v any) error {
+	mediaType, _, _ := mime.ParseMediaType(c.Response.Header().Get("Content-Type"))
+	if len(mediaType) > 0 {
+		codec := c.codec(mediaType)
+		if codec == nil {
+			return fmt.Errorf("quick: no codec for %s", mediaType)
+		}
+		return c.encode(codec, v)
+	}
+
+	c.Append("Vary", "Accept")
+	offers := c.codecTypes(v)
+	var encodeErr error
+	for {
+		if mediaType = c.Accepts(offers...); len(mediaType) == 0 {
+			if encodeErr != nil {
+				return ErrNotAcceptable.Wrap(encodeErr)
+			}
+			return ErrNotAcceptable
+		}
+
+		var buf bytes.Buffer
+		if encodeErr = c.codec(mediaType).Encode(&buf, v); encodeErr == nil {
+			c.Set("Content-Type", mediaType)
+			return c.writeResponse(buf.Bytes())
+		}
+
+		rest := make([]string, 0, len(offers)-1)
+		for _, offer := range offers {
+			if offer != mediaType {
+				rest = append(rest, offer)
+			}
+		}
+		offers = rest
+	}
+}
+
+// encode buffers the encoded v so a failure leaves the response untouched.
+func (c *Ctx) encode(codec Codec, v any) error {
+	var buf bytes.Buffer
+	if err := codec.Encode(&buf, v); err != nil {
+		return err
+	}
+	return c.writeResponse(buf.Bytes())
+}
+
+// bindCodec decodes the body into v with the codec of its Content-Type.
+// Bodies of unknown types are left alone. A body the codec can not
+// decode is answered with 400, errors that already carry a status, like
+// 413 past MaxBodySize, keep it.
+func bindCodec(c *Ctx, mediaType string, v any) error {
+	codec := c.codec(mediaType)
+	if codec == nil {
+		return nil
+	}
+	err := decodeBody(c, func(r io.Reader) error { return codec.Decode(r, v) })
+	var he *HTTPError
+	if err != nil && !errors.As(err, &he) {
+		return ErrBadRequest.Wrap(err)
+	}
+	return err
+}
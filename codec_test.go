@@ -0,0 +1,257 @@
+package quick
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// kvCodec reads and writes map[string]string as key=value lines.
+type kvCodec struct{}
+
+func (kvCodec) Decode(r io.Reader, v any) error {
+	m, ok := v.(*map[string]string)
+	if !ok {
+		return fmt.Errorf("kv: cannot decode into %T", v)
+	}
+	*m = make(map[string]string)
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		if k, val, ok := strings.Cut(sc.Text(), "="); ok {
+			(*m)[k] = val
+		}
+	}
+	return sc.Err()
+}
+
+func (kvCodec) Encode(w io.Writer, v any) error {
+	m, ok := v.(map[string]string)
+	if !ok {
+		return fmt.Errorf("kv: cannot encode %T", v)
+	}
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(w, "%s=%s\n", k, m[k])
+	}
+	return nil
+}
+
+// upperJSON marks the responses of a replaced JSON codec.
+type upperJSON struct{ jsonCodec }
+
+func (upperJSON) Encode(w io.Writer, v any) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write([]byte(strings.ToUpper(string(b))))
+	return err
+}
+
+// cover     -> go test -v -count=1 -cover -failfast -run ^TestQuick_RegisterCodec$
+// coverHTML -> go test -v -count=1 -failfast -cover -coverprofile=coverage.out -run ^TestQuick_RegisterCodec$; go tool cover -html=coverage.out
+func TestQuick_RegisterCodec(t *testing.T) {
+	q := New()
+	q.RegisterCodec("application/x-kv", kvCodec{})
+	q.Post("/echo", func(c *Ctx) error {
+		var m map[string]string
+		if err := c.Bind(&m); err != nil {
+			return err
+		}
+		return c.Encode(m)
+	})
+
+	tests := []struct {
+		name    string
+		headers map[string]string
+		body    string
+		code    int
+		ctype   string
+		want    string
+	}{
+		{
+			name:    "kv_to_kv",
+			headers: map[string]string{"Content-Type": "application/x-kv; charset=utf-8", "Accept": "application/x-kv"},
+			body:    "b=2\na=1\n",
+			code:    200,
+			ctype:   "application/x-kv",
+			want:    "a=1\nb=2\n",
+		},
+		{
+			name:    "json_to_kv",
+			headers: map[string]string{"Content-Type": "application/json;charset=UTF-8", "Accept": "application/json;q=0.5, application/x-kv"},
+			body:    `{"name":"jeff"}`,
+			code:    200,
+			ctype:   "application/x-kv",
+			want:    "name=jeff\n",
+		},
+		{
+			name:    "kv_to_json_by_default",
+			headers: map[string]string{"Content-Type": "Application/X-KV"},
+			body:    "name=jeff\n",
+			code:    200,
+			ctype:   ContentTypeAppJSON,
+			want:    `{"name":"jeff"}`,
+		},
+		{
+			name:    "structured_suffix",
+			headers: map[string]string{"Content-Type": "application/merge-patch+json", "Accept": "application/*"},
+			body:    `{"name":"jeff"}`,
+			code:    200,
+			ctype:   ContentTypeAppJSON,
+			want:    `{"name":"jeff"}`,
+		},
+		{
+			name:    "map_skips_xml",
+			headers: map[string]string{"Content-Type": "application/x-kv", "Accept": "application/xml, application/json;q=0.5"},
+			body:    "name=jeff\n",
+			code:    200,
+			ctype:   ContentTypeAppJSON,
+			want:    `{"name":"jeff"}`,
+		},
+		{
+			name:    "map_only_xml",
+			headers: map[string]string{"Content-Type": "application/x-kv", "Accept": "text/xml"},
+			body:    "name=jeff\n",
+			code:    406,
+			ctype:   ContentTypeTextXML,
+			want:    `<error><code>406</code><message>Not Acceptable</message></error>`,
+		},
+		{
+			name:    "not_acceptable",
+			headers: map[string]string{"Content-Type": "application/x-kv", "Accept": "image/png"},
+			body:    "name=jeff\n",
+			code:    406,
+			ctype:   "text/plain; charset=utf-8",
+			want:    "Not Acceptable",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := q.QuickTest("POST", "/echo", tt.headers, []byte(tt.body))
+			if err != nil {
+				t.Fatalf("error: %v", err)
+			}
+			if data.StatusCode() != tt.code || data.BodyStr() != tt.want {
+				t.Errorf("was suppose to return %d %q and %d %q come", tt.code, tt.want, data.StatusCode(), data.BodyStr())
+			}
+			if ct := data.Response().Header.Get("Content-Type"); ct != tt.ctype {
+				t.Errorf("was suppose to return Content-Type %s and %s come", tt.ctype, ct)
+			}
+		})
+	}
+}
+
+func TestCtx_EncodeContentType(t *testing.T) {
+	q := New()
+	q.RegisterCodec(ContentTypeAppJSON, upperJSON{})
+	q.RegisterCodec("application/x-kv", kvCodec{})
+	q.Get("/json", func(c *Ctx) error {
+		return c.Status(201).JSON(map[string]string{"name": "jeff"})
+	})
+	q.Get("/kv", func(c *Ctx) error {
+		c.Set("Content-Type", "application/x-kv; charset=utf-8")
+		return c.Encode(map[string]string{"name": "jeff"})
+	})
+	q.Get("/unknown", func(c *Ctx) error {
+		c.Set("Content-Type", "application/x-unknown")
+		return c.Encode(map[string]string{"name": "jeff"})
+	})
+
+	data, _ := q.QuickTest("GET", "/json", nil)
+	if data.StatusCode() != 201 || data.BodyStr() != `{"NAME":"JEFF"}` {
+		t.Errorf("was suppose to use the registered JSON codec and %d %s come", data.StatusCode(), data.BodyStr())
+	}
+
+	data, _ = q.QuickTest("GET", "/kv", map[string]string{"Accept": "application/json"})
+	if data.StatusCode() != 200 || data.BodyStr() != "name=jeff\n" {
+		t.Errorf("was suppose to follow the Content-Type set and %d %q come", data.StatusCode(), data.BodyStr())
+	}
+
+	data, _ = q.QuickTest("GET", "/unknown", nil)
+	if data.StatusCode() != 500 {
+		t.Errorf("was suppose to return 500 and %d come", data.StatusCode())
+	}
+}
+
+// cover -> go test -v -count=1 -cover -failfast -run ^TestQuick_BindMalformed$
+func TestQuick_BindMalformed(t *testing.T) {
+	type user struct {
+		Name string `json:"name" xml:"name"`
+	}
+
+	q := New(Config{MaxBodySize: 64})
+	handler := func(c *Ctx) error {
+		var u user
+		if err := c.Bind(&u); err != nil {
+			return err
+		}
+		return c.SendString(u.Name)
+	}
+	q.Post("/user", handler)
+	q.Post("/stream", handler, RouteConfig{NoBuffer: true})
+
+	tests := []struct {
+		route string
+		ctype string
+		body  string
+		code  int
+		want  string
+	}{
+		{route: "/user", ctype: ContentTypeAppJSON, body: `{"name":`, code: 400, want: "Bad Request"},
+		{route: "/user", ctype: ContentTypeAppJSON, body: ``, code: 400, want: "Bad Request"},
+		{route: "/user", ctype: ContentTypeAppXML, body: `<user><name>`, code: 400, want: "Bad Request"},
+		{route: "/stream", ctype: ContentTypeAppJSON, body: `{"name":`, code: 400, want: "Bad Request"},
+		{route: "/user", ctype: ContentTypeAppJSON, body: `{"name":"` + strings.Repeat("j", 64) + `"}`, code: 413, want: "Request Entity Too Large"},
+		{route: "/user", ctype: ContentTypeAppJSON, body: `{"name":"jeff"}`, code: 200, want: "jeff"},
+	}
+	for _, tt := range tests {
+		data, err := q.QuickTest("POST", tt.route, map[string]string{"Content-Type": tt.ctype}, []byte(tt.body))
+		if err != nil {
+			t.Fatalf("error: %v", err)
+		}
+		if data.StatusCode() != tt.code || data.BodyStr() != tt.want {
+			t.Errorf("%s %q: was suppose to return %d %s and %d %s come", tt.route, tt.body, tt.code, tt.want, data.StatusCode(), data.BodyStr())
+		}
+	}
+}
+
+// cover -> go test -v -count=1 -cover -failfast -run ^TestQuick_RegisterCodecOutsideRoutes$
+func TestQuick_RegisterCodecOutsideRoutes(t *testing.T) {
+	q := New(Config{ErrorHandler: func(c *Ctx, err error) {
+		c.Status(500).JSON(map[string]string{"error": err.Error()})
+	}})
+	q.RegisterCodec(ContentTypeAppJSON, upperJSON{})
+	q.NotFound(func(c *Ctx) error {
+		return c.JSON(map[string]int{"a": 1})
+	})
+	q.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/fail" {
+				HandleError(w, r, errors.New("boom"))
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	})
+	q.Get("/fail", func(c *Ctx) error { return nil })
+
+	data, _ := q.QuickTest("GET", "/nothing", nil)
+	if data.StatusCode() != 404 || data.BodyStr() != `{"A":1}` {
+		t.Errorf("was suppose to return 404 {\"A\":1} and %d %s come", data.StatusCode(), data.BodyStr())
+	}
+	data, _ = q.QuickTest("GET", "/fail", nil)
+	if data.StatusCode() != 500 || data.BodyStr() != `{"ERROR":"BOOM"}` {
+		t.Errorf("was suppose to return 500 {\"ERROR\":\"BOOM\"} and %d %s come", data.StatusCode(), data.BodyStr())
+	}
+}
@@ -0,0 +1,21 @@
+package quick
+
+import "net/http"
+
+type Ctx struct {
+	Response   http.ResponseWriter
+	Request    *http.Request
+	resStatus  int
+	bodyByte   []byte
+	bodyRead   bool
+	bodyErr    error
+	noBuffer   bool
+	maxBody    int64
+	formParsed bool
+	formErr    error
+	quick      *Quick
+	JsonStr    string
+	Headers    map[string][]string
+	Params     map[string]string
+	Query      map[string]string
+}
@@ -0,0 +1,109 @@
+package quick
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// decompressReader decodes a body sent with Content-Encoding. The decoder
+// is built on the first Read so a broken header surfaces as a body error
+// the handler sees through Bind, Body or BodyReader.
+type decompressReader struct {
+	body      io.ReadCloser
+	encodings []string
+	r         io.Reader
+	closers   []func()
+	err       error
+}
+
+// decompressBody replaces the body of req with its decoded content. The
+// codings are undone in the reverse order they were applied. Unsupported
+// ones fail every read with 415.
+func decompressBody(req *http.Request) {
+	var encodings []string
+	for _, enc := range strings.Split(req.Header.Get("Content-Encoding"), ",") {
+		if enc = strings.ToLower(strings.TrimSpace(enc)); len(enc) > 0 && enc != "identity" {
+			encodings = append(encodings, enc)
+		}
+	}
+	if len(encodings) == 0 {
+		req.Header.Del("Content-Encoding")
+		return
+	}
+
+	req.Body = &decompressReader{body: req.Body, encodings: encodings}
+	req.Header.Del("Content-Encoding")
+	req.Header.Del("Content-Length")
+	req.ContentLength = -1
+}
+
+func (d *decompressReader) Read(p []byte) (int, error) {
+	if d.r == nil && d.err == nil {
+		d.err = d.open()
+	}
+	if d.err != nil {
+		return 0, d.err
+	}
+
+	n, err := d.r.Read(p)
+	if err != nil {
+		d.release()
+		var tooLarge *http.MaxBytesError
+		if err != io.EOF && !errors.As(err, &tooLarge) {
+			err = ErrBadRequest.Wrap(err)
+		}
+		d.err = err
+	}
+	return n, err
+}
+
+func (d *decompressReader) open() error {
+	var r io.Reader = d.body
+	for i := len(d.encodings) - 1; i >= 0; i-- {
+		var err error
+		switch d.encodings[i] {
+		case "gzip", "x-gzip":
+			r, err = gzip.NewReader(r)
+		case "deflate":
+			r, err = zlib.NewReader(r)
+		case "zstd":
+			var zr *zstd.Decoder
+			// windows over 8MB are refused, as browsers do
+			zr, err = zstd.NewReader(r, zstd.WithDecoderConcurrency(1), zstd.WithDecoderMaxWindow(8<<20))
+			if err == nil {
+				d.closers = append(d.closers, zr.Close)
+			}
+			r = zr
+		default:
+			return ErrUnsupportedMedia.WithMessage("Unsupported Content-Encoding")
+		}
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				return err
+			}
+			return ErrBadRequest.Wrap(err)
+		}
+	}
+	d.r = r
+	return nil
+}
+
+// release frees the zstd decoders once the body is done.
+func (d *decompressReader) release() {
+	for _, close := range d.closers {
+		close()
+	}
+	d.closers = nil
+}
+
+func (d *decompressReader) Close() error {
+	d.release()
+	return d.body.Close()
+}
@@ -0,0 +1,136 @@
+package quick
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+func compressBody(t *testing.T, encoding string, data []byte) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	var err error
+	switch encoding {
+	case "gzip":
+		w := gzip.NewWriter(&buf)
+		w.Write(data)
+		err = w.Close()
+	case "deflate":
+		w := zlib.NewWriter(&buf)
+		w.Write(data)
+		err = w.Close()
+	case "zstd":
+		var w *zstd.Encoder
+		if w, err = zstd.NewWriter(&buf); err == nil {
+			w.Write(data)
+			err = w.Close()
+		}
+	}
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// cover     -> go test -v -count=1 -cover -failfast -run ^TestQuick_DecompressBody$
+// coverHTML -> go test -v -count=1 -failfast -cover -coverprofile=coverage.out -run ^TestQuick_DecompressBody$; go tool cover -html=coverage.out
+func TestQuick_DecompressBody(t *testing.T) {
+	type user struct {
+		Name string `json:"name"`
+	}
+
+	q := New(Config{MaxBodySize: 64 * 1024, DecompressBody: true})
+	q.Post("/v1/user", func(c *Ctx) error {
+		var u user
+		if err := c.Bind(&u); err != nil {
+			return err
+		}
+		if len(c.Request.Header.Get("Content-Encoding")) > 0 {
+			t.Errorf("was suppose to remove Content-Encoding and %q come", c.Request.Header.Get("Content-Encoding"))
+		}
+		return c.SendString(u.Name)
+	})
+
+	payload := []byte(`{"name":"jeff"}`)
+	gzipped := compressBody(t, "gzip", payload)
+	bomb := compressBody(t, "gzip", append([]byte(`{"name":"`), bytes.Repeat([]byte("a"), 1<<20)...))
+
+	tests := []struct {
+		name     string
+		encoding string
+		body     []byte
+		code     int
+		want     string
+	}{
+		{name: "gzip", encoding: "gzip", body: gzipped, code: 200, want: "jeff"},
+		{name: "deflate", encoding: "deflate", body: compressBody(t, "deflate", payload), code: 200, want: "jeff"},
+		{name: "zstd", encoding: "zstd", body: compressBody(t, "zstd", payload), code: 200, want: "jeff"},
+		{name: "stacked", encoding: "gzip, zstd", body: compressBody(t, "zstd", gzipped), code: 200, want: "jeff"},
+		{name: "identity", encoding: "identity", body: payload, code: 200, want: "jeff"},
+		{name: "zip_bomb", encoding: "gzip", body: bomb, code: 413, want: "Request Entity Too Large"},
+		{name: "corrupt", encoding: "gzip", body: []byte("not gzip"), code: 400, want: "Bad Request"},
+		{name: "unsupported", encoding: "br", body: payload, code: 415, want: "Unsupported Content-Encoding"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if len(tt.body) > 64*1024 {
+				t.Fatalf("the compressed body must fit MaxBodySize, %d bytes", len(tt.body))
+			}
+			headers := map[string]string{"Content-Type": "application/json", "Content-Encoding": tt.encoding}
+			data, err := q.QuickTest("POST", "/v1/user", headers, tt.body)
+			if err != nil {
+				t.Fatalf("error: %v", err)
+			}
+			if data.StatusCode() != tt.code || data.BodyStr() != tt.want {
+				t.Errorf("was suppose to return %d %s and %d %s come", tt.code, tt.want, data.StatusCode(), data.BodyStr())
+			}
+		})
+	}
+
+	t.Run("route_opt_in", func(t *testing.T) {
+		q := New()
+		q.Post("/plain", func(c *Ctx) error {
+			return c.Byte(c.Body())
+		})
+		q.Post("/decoded", func(c *Ctx) error {
+			return c.Byte(c.Body())
+		}, RouteConfig{DecompressBody: true})
+
+		headers := map[string]string{"Content-Encoding": "gzip"}
+		data, _ := q.QuickTest("POST", "/plain", headers, gzipped)
+		if !bytes.Equal(data.Body(), gzipped) {
+			t.Errorf("was suppose to keep the compressed body and %q come", data.BodyStr())
+		}
+		data, _ = q.QuickTest("POST", "/decoded", headers, gzipped)
+		if data.BodyStr() != string(payload) {
+			t.Errorf("was suppose to decompress the body and %q come", data.BodyStr())
+		}
+	})
+
+	t.Run("no_buffer", func(t *testing.T) {
+		q := New(Config{MaxBodySize: 1024, DecompressBody: true})
+		q.Post("/stream", func(c *Ctx) error {
+			b, err := io.ReadAll(c.BodyReader())
+			if err != nil {
+				return err
+			}
+			return c.Byte(b)
+		}, RouteConfig{NoBuffer: true})
+
+		data, _ := q.QuickTest("POST", "/stream", map[string]string{"Content-Encoding": "zstd"}, compressBody(t, "zstd", payload))
+		if data.StatusCode() != 200 || data.BodyStr() != string(payload) {
+			t.Errorf("was suppose to stream the decompressed body and %d %s come", data.StatusCode(), data.BodyStr())
+		}
+
+		body := compressBody(t, "zstd", bytes.Repeat([]byte("x"), 4096))
+		data, _ = q.QuickTest("POST", "/stream", map[string]string{"Content-Encoding": "zstd"}, body)
+		if data.StatusCode() != 413 {
+			t.Errorf("was suppose to return 413 and %d come", data.StatusCode())
+		}
+	})
+}
@@ -0,0 +1,136 @@
+package quick
+
+import (
+	"encoding/xml"
+	"errors"
+	"net/http"
+
+	"github.com/jeffotoni/quick/internal/concat"
+)
+
+// HTTPError is an error that carries the status code and message sent to
+// the client. Returning one from a HandleFunc replaces the default 500.
+type HTTPError struct {
+	Code    int
+	Message string
+	Details any
+	Err     error
+}
+
+var (
+	ErrBadRequest          = NewError(http.StatusBadRequest)
+	ErrUnauthorized        = NewError(http.StatusUnauthorized)
+	ErrForbidden           = NewError(http.StatusForbidden)
+	ErrNotFound            = NewError(http.StatusNotFound)
+	ErrMethodNotAllowed    = NewError(http.StatusMethodNotAllowed)
+	ErrNotAcceptable       = NewError(http.StatusNotAcceptable)
+	ErrConflict            = NewError(http.StatusConflict)
+	ErrRequestTooLarge     = NewError(http.StatusRequestEntityTooLarge)
+	ErrUnsupportedMedia    = NewError(http.StatusUnsupportedMediaType)
+	ErrUnprocessableEntity = NewError(http.StatusUnprocessableEntity)
+	ErrTooManyRequests     = NewError(http.StatusTooManyRequests)
+	ErrInternalServerError = NewError(http.StatusInternalServerError)
+	ErrServiceUnavailable  = NewError(http.StatusServiceUnavailable)
+)
+
+// NewError creates an HTTPError. The message defaults to the status text.
+func NewError(code int, message ...string) *HTTPError {
+	e := &HTTPError{Code: code, Message: http.StatusText(code)}
+	if len(message) > 0 {
+		e.Message = message[0]
+	}
+	return e
+}
+
+func (e *HTTPError) Error() string {
+	if e.Err != nil {
+		return concat.String(e.Message, ": ", e.Err.Error())
+	}
+	return e.Message
+}
+
+func (e *HTTPError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is an HTTPError with the same code and message,
+// so errors.Is(err, quick.ErrNotFound) works on copies made by Wrap and
+// WithDetails.
+func (e *HTTPError) Is(target error) bool {
+	t, ok := target.(*HTTPError)
+	return ok && t.Code == e.Code && t.Message == e.Message
+}
+
+// Wrap returns a copy of e wrapping err. err is kept for logs and
+// errors.Is/As, it is never sent to the client.
+func (e *HTTPError) Wrap(err error) *HTTPError {
+	c := *e
+	c.Err = err
+	return &c
+}
+
+// WithDetails returns a copy of e with a details payload for the client.
+func (e *HTTPError) WithDetails(details any) *HTTPError {
+	c := *e
+	c.Details = details
+	return &c
+}
+
+// WithMessage returns a copy of e with another message.
+func (e *HTTPError) WithMessage(message string) *HTTPError {
+	c := *e
+	c.Message = message
+	return &c
+}
+
+type errorBody struct {
+	XMLName xml.Name `json:"-" xml:"error"`
+	Code    int      `json:"code" xml:"code"`
+	Message string   `json:"message" xml:"message"`
+	Details any      `json:"details,omitempty" xml:"details,omitempty"`
+}
+
+// sendHTTPError writes e as JSON or XML when the request Accept header asks
+// for it and as plain text otherwise. Errors with details, like the field
+// list of a failed validation, default to JSON since plain text would
+// drop them.
+func sendHTTPError(c *Ctx, e *HTTPError) {
+	body := errorBody{Code: e.Code, Message: e.Message, Details: e.Details}
+	c.Status(e.Code)
+
+	offers := []string{ContentTypeTextPlain, ContentTypeAppJSON, ContentTypeAppXML, ContentTypeTextXML}
+	if e.Details != nil {
+		offers = []string{ContentTypeAppJSON, ContentTypeAppXML, ContentTypeTextXML, ContentTypeTextPlain}
+	}
+	switch c.Accepts(offers...) {
+	case ContentTypeAppJSON:
+		if c.JSON(body) == nil {
+			return
+		}
+	case ContentTypeAppXML, ContentTypeTextXML:
+		if c.XML(body) == nil {
+			return
+		}
+	}
+
+	c.Set("Content-Type", "text/plain; charset=utf-8")
+	c.SendString(e.Message)
+}
+
+func defaultErrorHandler(c *Ctx, err error) {
+	var he *HTTPError
+	if errors.As(err, &he) {
+		sendHTTPError(c, he)
+		return
+	}
+
+	// a body read through BodyReader ran past MaxBodySize
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		sendHTTPError(c, ErrRequestTooLarge)
+		return
+	}
+
+	c.Set("Content-Type", "text/plain; charset=utf-8")
+	c.Status(500).SendString(err.Error())
+}
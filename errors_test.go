@@ -0,0 +1,112 @@
+package quick
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+// cover     -> go test -v -count=1 -cover -failfast -run ^TestQuick_HTTPError$
+// coverHTML -> go test -v -count=1 -failfast -cover -coverprofile=coverage.out -run ^TestQuick_HTTPError$; go tool cover -html=coverage.out
+func TestQuick_HTTPError(t *testing.T) {
+	type args struct {
+		err        error
+		reqHeaders map[string]string
+		wantCode   int
+		wantOut    string
+		wantCT     string
+	}
+
+	tests := []struct {
+		name string
+		args args
+	}{
+		{
+			name: "plain_text",
+			args: args{err: ErrNotFound, wantCode: 404, wantOut: "Not Found", wantCT: "text/plain; charset=utf-8"},
+		},
+		{
+			name: "custom_message",
+			args: args{err: NewError(http.StatusBadRequest, "name is required"), wantCode: 400, wantOut: "name is required", wantCT: "text/plain; charset=utf-8"},
+		},
+		{
+			name: "json",
+			args: args{
+				err:        ErrBadRequest.WithDetails(map[string]string{"name": "required"}),
+				reqHeaders: map[string]string{"Accept": "application/json"},
+				wantCode:   400,
+				wantOut:    `{"code":400,"message":"Bad Request","details":{"name":"required"}}`,
+				wantCT:     ContentTypeAppJSON,
+			},
+		},
+		{
+			name: "xml",
+			args: args{
+				err:        ErrConflict.WithMessage("user exists"),
+				reqHeaders: map[string]string{"Accept": "application/xml"},
+				wantCode:   409,
+				wantOut:    `<error><code>409</code><message>user exists</message></error>`,
+				wantCT:     ContentTypeTextXML,
+			},
+		},
+		{
+			name: "wrapped_by_fmt",
+			args: args{err: fmt.Errorf("loading user: %w", ErrForbidden), wantCode: 403, wantOut: "Forbidden", wantCT: "text/plain; charset=utf-8"},
+		},
+		{
+			name: "wrapping_internal_error_is_not_sent",
+			args: args{err: ErrServiceUnavailable.Wrap(errors.New("dial tcp 10.0.0.1:5432")), wantCode: 503, wantOut: "Service Unavailable", wantCT: "text/plain; charset=utf-8"},
+		},
+		{
+			name: "plain_error",
+			args: args{err: errors.New("boom"), wantCode: 500, wantOut: "boom", wantCT: "text/plain; charset=utf-8"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := New()
+			r.Get("/err", func(c *Ctx) error { return tt.args.err })
+
+			data, err := r.QuickTest("GET", "/err", tt.args.reqHeaders)
+			if err != nil {
+				t.Errorf("error: %v", err)
+				return
+			}
+
+			if data.BodyStr() != tt.args.wantOut {
+				t.Errorf("was suppose to return %s and %s come", tt.args.wantOut, data.BodyStr())
+			}
+
+			if data.StatusCode() != tt.args.wantCode {
+				t.Errorf("was suppose to return %d and %d come", tt.args.wantCode, data.StatusCode())
+			}
+
+			if ct := data.Response().Header.Get("Content-Type"); ct != tt.args.wantCT {
+				t.Errorf("was suppose to return Content-Type %s and %s come", tt.args.wantCT, ct)
+			}
+		})
+	}
+}
+
+func TestHTTPError_Is(t *testing.T) {
+	inner := errors.New("no rows")
+	err := fmt.Errorf("repo: %w", ErrNotFound.Wrap(inner).WithDetails("id=7"))
+
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("errors.Is(err, ErrNotFound) = false, want true")
+	}
+	if errors.Is(err, ErrBadRequest) {
+		t.Errorf("errors.Is(err, ErrBadRequest) = true, want false")
+	}
+	if !errors.Is(err, inner) {
+		t.Errorf("errors.Is(err, inner) = false, want true")
+	}
+	if ErrNotFound.Details != nil || ErrNotFound.Err != nil {
+		t.Errorf("WithDetails and Wrap must not change the shared ErrNotFound")
+	}
+	if got := err.Error(); got != "repo: Not Found: no rows" {
+		t.Errorf("Error() = %q", got)
+	}
+}
@@ -0,0 +1,141 @@
+package quick
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"os"
+)
+
+const (
+	ContentTypeFormURLEncoded = `application/x-www-form-urlencoded`
+	ContentTypeMultipartForm  = `multipart/form-data`
+)
+
+// parseForm parses the urlencoded or multipart body once. Multipart
+// parts are kept in memory up to the body limit of the route; since the
+// body itself is limited to it too, so is what goes to disk.
+func (c *Ctx) parseForm() error {
+	if c.formParsed {
+		return c.formErr
+	}
+	c.formParsed = true
+
+	mediaType, _, _ := mime.ParseMediaType(c.Request.Header.Get("Content-Type"))
+	if mediaType == ContentTypeFormURLEncoded || mediaType == ContentTypeMultipartForm {
+		if c.bodyRead {
+			c.Request.Body = io.NopCloser(bytes.NewReader(c.bodyByte))
+		}
+		// the form consumes the body, other types only parse the query
+		c.bodyRead = true
+	}
+
+	var err error
+	if mediaType == ContentTypeMultipartForm {
+		err = c.Request.ParseMultipartForm(c.formMemory())
+	} else {
+		err = c.Request.ParseForm()
+	}
+	if err != nil {
+		if err = bodyError(err); !errors.Is(err, ErrRequestTooLarge) {
+			err = ErrBadRequest.Wrap(err)
+		}
+		c.formErr = err
+	}
+	return c.formErr
+}
+
+func (c *Ctx) formMemory() int64 {
+	if c.maxBody > 0 {
+		return c.maxBody
+	}
+	return defaultConfig.MaxBodySize
+}
+
+// removeForm deletes the temporary files of a multipart form. The server
+// only cleans up the request it created, not the copy routes run with.
+func (c *Ctx) removeForm() {
+	if c.formParsed && c.Request.MultipartForm != nil {
+		c.Request.MultipartForm.RemoveAll()
+	}
+}
+
+// FormValue returns the first value of key in the urlencoded or
+// multipart body, or in the query string when the body has none.
+// The form consumes the body, Body is empty afterwards unless it was
+// read first.
+func (c *Ctx) FormValue(key string) string {
+	if c.parseForm() != nil {
+		return ""
+	}
+	if vs := c.Request.Form[key]; len(vs) > 0 {
+		return vs[0]
+	}
+	return ""
+}
+
+// MultipartForm returns the parsed multipart/form-data body.
+func (c *Ctx) MultipartForm() (*multipart.Form, error) {
+	if err := c.parseForm(); err != nil {
+		return nil, err
+	}
+	if c.Request.MultipartForm == nil {
+		return nil, ErrBadRequest.Wrap(http.ErrNotMultipart)
+	}
+	return c.Request.MultipartForm, nil
+}
+
+// FormFile returns the first file uploaded under key.
+func (c *Ctx) FormFile(key string) (*multipart.FileHeader, error) {
+	form, err := c.MultipartForm()
+	if err != nil {
+		return nil, err
+	}
+	if fhs := form.File[key]; len(fhs) > 0 {
+		return fhs[0], nil
+	}
+	return nil, ErrBadRequest.Wrap(http.ErrMissingFile)
+}
+
+// SaveFile writes an uploaded file to path.
+func (c *Ctx) SaveFile(fh *multipart.FileHeader, path string) error {
+	src, err := fh.Open()
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(dst, src); err != nil {
+		dst.Close()
+		return err
+	}
+	return dst.Close()
+}
+
+// valuesOf looks names up in a url.Values-like map.
+func valuesOf(values map[string][]string) func(string) []string {
+	return func(name string) []string {
+		return values[name]
+	}
+}
+
+// bindForm fills v from the urlencoded or multipart body using form tags.
+func bindForm(c *Ctx, v any) error {
+	if err := c.parseForm(); err != nil {
+		return err
+	}
+
+	var files map[string][]*multipart.FileHeader
+	if c.Request.MultipartForm != nil {
+		files = c.Request.MultipartForm.File
+	}
+	return bindError(bindValues(v, "form", valuesOf(c.Request.PostForm), files))
+}
@@ -0,0 +1,157 @@
+package quick
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func multipartBody(t *testing.T, fields map[string]string, files map[string]string) (string, []byte) {
+	t.Helper()
+
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	for k, v := range fields {
+		w.WriteField(k, v)
+	}
+	for name, content := range files {
+		fw, err := w.CreateFormFile(name, name+".txt")
+		if err != nil {
+			t.Fatalf("error: %v", err)
+		}
+		fw.Write([]byte(content))
+	}
+	w.Close()
+	return w.FormDataContentType(), buf.Bytes()
+}
+
+// cover     -> go test -v -count=1 -cover -failfast -run ^TestCtx_BindForm$
+// coverHTML -> go test -v -count=1 -failfast -cover -coverprofile=coverage.out -run ^TestCtx_BindForm$; go tool cover -html=coverage.out
+func TestCtx_BindForm(t *testing.T) {
+	type signup struct {
+		Name     string                `form:"name"`
+		Age      int                   `form:"age"`
+		Admin    *bool                 `form:"admin"`
+		Tags     []string              `form:"tag"`
+		Birthday time.Time             `form:"birthday"`
+		Avatar   *multipart.FileHeader `form:"avatar"`
+		Ignored  string                `form:"-"`
+	}
+
+	var got signup
+	q := New()
+	q.Post("/signup", func(c *Ctx) error {
+		got = signup{}
+		return c.Bind(&got)
+	})
+
+	t.Run("urlencoded", func(t *testing.T) {
+		body := []byte("name=jeff&age=35&admin=true&tag=go&tag=http&birthday=1988-05-10T00:00:00Z&Ignored=x")
+		data, _ := q.QuickTest("POST", "/signup", map[string]string{"Content-Type": "application/x-www-form-urlencoded"}, body)
+		if data.StatusCode() != 200 {
+			t.Fatalf("was suppose to return 200 and %d %s come", data.StatusCode(), data.BodyStr())
+		}
+		if got.Name != "jeff" || got.Age != 35 || got.Admin == nil || !*got.Admin ||
+			strings.Join(got.Tags, ",") != "go,http" || got.Birthday.Year() != 1988 || len(got.Ignored) > 0 {
+			t.Errorf("was suppose to bind the form and %+v come", got)
+		}
+	})
+
+	t.Run("multipart", func(t *testing.T) {
+		contentType, body := multipartBody(t, map[string]string{"name": "jeff", "age": "35"}, map[string]string{"avatar": "png"})
+		data, _ := q.QuickTest("POST", "/signup", map[string]string{"Content-Type": contentType}, body)
+		if data.StatusCode() != 200 {
+			t.Fatalf("was suppose to return 200 and %d %s come", data.StatusCode(), data.BodyStr())
+		}
+		if got.Name != "jeff" || got.Age != 35 || got.Avatar == nil || got.Avatar.Filename != "avatar.txt" {
+			t.Errorf("was suppose to bind the form and %+v come", got)
+		}
+	})
+
+	t.Run("invalid_value", func(t *testing.T) {
+		data, _ := q.QuickTest("POST", "/signup", map[string]string{"Content-Type": "application/x-www-form-urlencoded"}, []byte("age=old"))
+		if data.StatusCode() != 400 {
+			t.Errorf("was suppose to return 400 and %d come", data.StatusCode())
+		}
+	})
+}
+
+// go test -v -count=1 -failfast -run ^TestCtx_FormFile$
+func TestCtx_FormFile(t *testing.T) {
+	dir := t.TempDir()
+
+	q := New(Config{MaxBodySize: 1024})
+	q.Post("/upload", func(c *Ctx) error {
+		fh, err := c.FormFile("doc")
+		if err != nil {
+			return err
+		}
+		if err := c.SaveFile(fh, filepath.Join(dir, fh.Filename)); err != nil {
+			return err
+		}
+		return c.SendString(c.FormValue("title"))
+	})
+
+	contentType, body := multipartBody(t, map[string]string{"title": "report"}, map[string]string{"doc": "file content"})
+	data, _ := q.QuickTest("POST", "/upload", map[string]string{"Content-Type": contentType}, body)
+	if data.StatusCode() != 200 || data.BodyStr() != "report" {
+		t.Errorf("was suppose to return 200 report and %d %s come", data.StatusCode(), data.BodyStr())
+	}
+	if b, err := os.ReadFile(filepath.Join(dir, "doc.txt")); err != nil || string(b) != "file content" {
+		t.Errorf("was suppose to save the file and %q %v come", b, err)
+	}
+
+	contentType, body = multipartBody(t, nil, map[string]string{"other": "x"})
+	data, _ = q.QuickTest("POST", "/upload", map[string]string{"Content-Type": contentType}, body)
+	if data.StatusCode() != 400 {
+		t.Errorf("missing file: was suppose to return 400 and %d come", data.StatusCode())
+	}
+
+	contentType, body = multipartBody(t, nil, map[string]string{"doc": strings.Repeat("x", 2048)})
+	data, _ = q.QuickTest("POST", "/upload", map[string]string{"Content-Type": contentType}, body)
+	if data.StatusCode() != 413 {
+		t.Errorf("too large: was suppose to return 413 and %d come", data.StatusCode())
+	}
+
+	t.Run("not_multipart", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/upload", strings.NewReader("title=x"))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		rec := httptest.NewRecorder()
+		q.ServeHTTP(rec, req)
+		if rec.Code != 400 {
+			t.Errorf("was suppose to return 400 and %d come", rec.Code)
+		}
+	})
+}
+
+// cover -> go test -v -count=1 -cover -failfast -run ^TestCtx_FormValueKeepsBody$
+func TestCtx_FormValueKeepsBody(t *testing.T) {
+	type user struct {
+		Name string `json:"name"`
+	}
+
+	q := New()
+	q.Post("/v1/user", func(c *Ctx) error {
+		// a JSON body has no form fields, only the query does
+		from := c.FormValue("from")
+		var u user
+		if err := c.Bind(&u); err != nil {
+			return err
+		}
+		return c.SendString(u.Name + " " + from + " " + c.BodyString())
+	})
+
+	data, err := q.QuickTest("POST", "/v1/user?from=app", map[string]string{"Content-Type": "application/json"}, []byte(`{"name":"jeff"}`))
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	want := `jeff app {"name":"jeff"}`
+	if data.StatusCode() != 200 || data.BodyStr() != want {
+		t.Errorf("was suppose to return 200 %s and %d %s come", want, data.StatusCode(), data.BodyStr())
+	}
+}
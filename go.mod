@@ -3,3 +3,12 @@ module github.com/jeffotoni/quick
 go 1.20
 
 require github.com/golang-jwt/jwt/v4 v4.5.0
+
+require (
+	github.com/andybalholm/brotli v1.1.1
+	github.com/klauspost/compress v1.17.9
+	golang.org/x/net v0.35.0
+	google.golang.org/protobuf v1.34.2
+)
+
+require golang.org/x/text v0.22.0 // indirect
@@ -2,6 +2,7 @@ package quick
 
 import (
 	"net/http"
+	"strings"
 
 	"github.com/jeffotoni/quick/internal/concat"
 )
@@ -22,58 +23,52 @@ func (q *Quick) Group(prefix string) *Group {
 	return g
 }
 
-func (g *Group) Get(pattern string, handlerFunc HandleFunc) {
-	pattern = concat.String(g.prefix, pattern)
-	path, params, partternExist := extractParamsPattern(pattern)
-
-	route := Route{
-		Pattern: partternExist,
-		Path:    path,
-		Params:  params,
-		handler: extractParamsGet(path, params, handlerFunc),
-		Method:  http.MethodGet,
-		Group:   g.prefix,
-	}
+func (g *Group) Get(pattern string, handlerFunc HandleFunc, opts ...RouteConfig) {
+	g.handle(http.MethodGet, pattern, handlerFunc, opts)
+}
 
-	g.quick.appendRoute(&route)
-	g.quick.mux.HandleFunc(path, route.handler)
+func (g *Group) Post(pattern string, handlerFunc HandleFunc, opts ...RouteConfig) {
+	g.handle(http.MethodPost, pattern, handlerFunc, opts)
 }
 
-func (g *Group) Post(pattern string, handlerFunc HandleFunc) {
-	pattern = concat.String(g.prefix, pattern)
-	_, params, partternExist := extractParamsPattern(pattern)
+func (g *Group) Put(pattern string, handlerFunc HandleFunc, opts ...RouteConfig) {
+	g.handle(http.MethodPut, pattern, handlerFunc, opts)
+}
 
-	pathPost := concat.String("post#", pattern)
+func (g *Group) Patch(pattern string, handlerFunc HandleFunc, opts ...RouteConfig) {
+	g.handle(http.MethodPatch, pattern, handlerFunc, opts)
+}
 
-	route := Route{
-		Pattern: partternExist,
-		Path:    pattern,
-		handler: extractParamsPost(g.quick, pattern, handlerFunc),
-		Method:  http.MethodPost,
-		Params:  params,
-		Group:   g.prefix,
-	}
+func (g *Group) Delete(pattern string, handlerFunc HandleFunc, opts ...RouteConfig) {
+	g.handle(http.MethodDelete, pattern, handlerFunc, opts)
+}
 
-	g.quick.appendRoute(&route)
-	g.quick.mux.HandleFunc(pathPost, route.handler)
+func (g *Group) Options(pattern string, handlerFunc HandleFunc, opts ...RouteConfig) {
+	g.handle(http.MethodOptions, pattern, handlerFunc, opts)
 }
 
-func (g *Group) Put(pattern string, handlerFunc HandleFunc) {
-	pattern = concat.String(g.prefix, pattern)
-	_, params, partternExist := extractParamsPattern(pattern)
+func (g *Group) Head(pattern string, handlerFunc HandleFunc, opts ...RouteConfig) {
+	g.handle(http.MethodHead, pattern, handlerFunc, opts)
+}
 
-	pathPut := concat.String("put#", pattern)
+func (g *Group) Connect(pattern string, handlerFunc HandleFunc, opts ...RouteConfig) {
+	g.handle(http.MethodConnect, pattern, handlerFunc, opts)
+}
+
+func (g *Group) Trace(pattern string, handlerFunc HandleFunc, opts ...RouteConfig) {
+	g.handle(http.MethodTrace, pattern, handlerFunc, opts)
+}
 
-	// Setting up the group
-	route := Route{
-		Pattern: partternExist,
-		Path:    pattern,
-		handler: extractParamsPut(g.quick, pattern, handlerFunc),
-		Method:  http.MethodPut,
-		Params:  params,
-		Group:   g.prefix,
+func (g *Group) Any(pattern string, handlerFunc HandleFunc, opts ...RouteConfig) {
+	g.Match(methods, pattern, handlerFunc, opts...)
+}
+
+func (g *Group) Match(methods []string, pattern string, handlerFunc HandleFunc, opts ...RouteConfig) {
+	for _, method := range methods {
+		g.handle(strings.ToUpper(method), pattern, handlerFunc, opts)
 	}
+}
 
-	g.quick.appendRoute(&route)
-	g.quick.mux.HandleFunc(pathPut, route.handler)
+func (g *Group) handle(method, pattern string, handlerFunc HandleFunc, opts []RouteConfig) {
+	g.quick.handle(method, g.prefix, concat.String(g.prefix, pattern), handlerFunc, opts)
 }
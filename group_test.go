@@ -343,3 +343,57 @@ func TestQuick_PutGroup(t *testing.T) {
 		})
 	}
 }
+
+// cover     ->  go test -v -count=1 -cover -failfast -run ^TestQuick_GroupMethods$
+// coverHTML ->  go test -v -count=1 -failfast -cover -coverprofile=coverage.out -run ^TestQuick_GroupMethods$; go tool cover -html=coverage.out
+func TestQuick_GroupMethods(t *testing.T) {
+	echo := func(c *Ctx) error {
+		return c.Status(200).SendString(concat.String(c.Request.Method, " ", c.Param("id")))
+	}
+
+	r := New()
+	g := r.Group("/v1")
+	g.Delete("/user/:id", echo)
+	g.Patch("/user/:id", echo)
+	g.Options("/user/:id", echo)
+	g.Head("/user/:id", echo)
+	g.Connect("/user/:id", echo)
+	g.Trace("/user/:id", echo)
+	g.Any("/any/:id", echo)
+	g.Match([]string{"PUT"}, "/match/:id", echo)
+
+	tests := []struct {
+		method  string
+		route   string
+		wantOut string
+	}{
+		{"DELETE", "/v1/user/1", "DELETE 1"},
+		{"PATCH", "/v1/user/1", "PATCH 1"},
+		{"OPTIONS", "/v1/user/1", "OPTIONS 1"},
+		{"HEAD", "/v1/user/1", ""},
+		{"CONNECT", "/v1/user/1", "CONNECT 1"},
+		{"TRACE", "/v1/user/1", "TRACE 1"},
+		{"GET", "/v1/any/1", "GET 1"},
+		{"PATCH", "/v1/any/1", "PATCH 1"},
+		{"PUT", "/v1/match/1", "PUT 1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(concat.String(tt.method, tt.route), func(t *testing.T) {
+			data, err := r.QuickTest(tt.method, tt.route, nil)
+			if err != nil {
+				t.Errorf("error: %v", err)
+				return
+			}
+			if data.StatusCode() != 200 || data.BodyStr() != tt.wantOut {
+				t.Errorf("was suppose to return 200 %q and %d %q come", tt.wantOut, data.StatusCode(), data.BodyStr())
+			}
+		})
+	}
+
+	for _, route := range r.GetRoute() {
+		if route.Group != "/v1" {
+			t.Errorf("route %s %s should belong to group /v1, got %q", route.Method, route.Path, route.Group)
+		}
+	}
+}
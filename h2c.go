@@ -0,0 +1,30 @@
+package quick
+
+import (
+	"net/http"
+
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+)
+
+// ListenH2C serves cleartext HTTP/2 on addr, for proxies and meshes that
+// speak HTTP/2 to their upstreams without TLS. It turns Config.H2C on and
+// then works like Listen, HTTP/1.1 clients are still served.
+func (q *Quick) ListenH2C(addr string, handler ...http.Handler) error {
+	q.config.H2C = true
+	return q.Listen(addr, handler...)
+}
+
+// enableH2C makes server accept prior knowledge h2c connections and
+// "Upgrade: h2c" requests on top of HTTP/1.1. The HTTP/2 side is
+// registered on the server so Shutdown also drains those connections.
+func (q *Quick) enableH2C(server *http.Server) error {
+	h2s := &http2.Server{
+		IdleTimeout: q.config.IdleTimeout,
+	}
+	if err := http2.ConfigureServer(server, h2s); err != nil {
+		return err
+	}
+	server.Handler = h2c.NewHandler(server.Handler, h2s)
+	return nil
+}
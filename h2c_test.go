@@ -0,0 +1,88 @@
+package quick
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/jeffotoni/quick/internal/concat"
+	"github.com/jeffotoni/quick/middleware/cors"
+	"golang.org/x/net/http2"
+)
+
+// cover     -> go test -v -count=1 -cover -failfast -run ^TestQuick_ListenH2C$
+// coverHTML -> go test -v -count=1 -failfast -cover -coverprofile=coverage.out -run ^TestQuick_ListenH2C$; go tool cover -html=coverage.out
+func TestQuick_ListenH2C(t *testing.T) {
+	addr := freeAddr(t)
+
+	q := New()
+	q.Use(cors.New(), "cors")
+	q.Post("/v1/user", func(c *Ctx) error {
+		return c.SendString(concat.String(c.Request.Proto, " ", c.BodyString()))
+	})
+	q.Get("/ping", func(c *Ctx) error { return c.SendString(c.Request.Proto) })
+
+	go q.ListenH2C(addr)
+	defer q.Shutdown(context.Background())
+	waitServer(t, "http://"+addr+"/ping")
+
+	t.Run("prior_knowledge", func(t *testing.T) {
+		client := &http.Client{Transport: &http2.Transport{
+			AllowHTTP: true,
+			DialTLSContext: func(ctx context.Context, network, addr string, _ *tls.Config) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, network, addr)
+			},
+		}}
+
+		resp, err := client.Post("http://"+addr+"/v1/user", ContentTypeAppJSON, strings.NewReader(`{"name":"quick"}`))
+		if err != nil {
+			t.Fatalf("error: %v", err)
+		}
+		defer resp.Body.Close()
+		b, _ := io.ReadAll(resp.Body)
+
+		if string(b) != `HTTP/2.0 {"name":"quick"}` {
+			t.Errorf("was suppose to return HTTP/2.0 and %s come", b)
+		}
+		if resp.Header.Get("Access-Control-Allow-Origin") != "*" {
+			t.Errorf("cors headers should be set on h2c responses")
+		}
+	})
+
+	t.Run("upgrade", func(t *testing.T) {
+		conn, err := net.Dial("tcp", addr)
+		if err != nil {
+			t.Fatalf("error: %v", err)
+		}
+		defer conn.Close()
+
+		io.WriteString(conn, "GET /ping HTTP/1.1\r\nHost: "+addr+"\r\n"+
+			"Connection: Upgrade, HTTP2-Settings\r\nUpgrade: h2c\r\nHTTP2-Settings: AAMAAABkAARAAAAAAAIAAAAA\r\n\r\n")
+
+		status, err := bufio.NewReader(conn).ReadString('\n')
+		if err != nil {
+			t.Fatalf("error: %v", err)
+		}
+		if !strings.HasPrefix(status, "HTTP/1.1 101") {
+			t.Errorf("was suppose to switch protocols and %q come", status)
+		}
+	})
+
+	t.Run("http1", func(t *testing.T) {
+		resp, err := http.Get("http://" + addr + "/ping")
+		if err != nil {
+			t.Fatalf("error: %v", err)
+		}
+		defer resp.Body.Close()
+		b, _ := io.ReadAll(resp.Body)
+		if string(b) != "HTTP/1.1" {
+			t.Errorf("was suppose to return HTTP/1.1 and %s come", b)
+		}
+	})
+}
@@ -0,0 +1,87 @@
+package quick
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// listenFdsStart is the first file descriptor passed by socket activation,
+// right after stdin, stdout and stderr.
+const listenFdsStart = 3
+
+// ListenUnix serves on the unix domain socket at path, e.g. behind a local
+// nginx. A stale socket file left by a previous run is removed first and
+// the new one gets mode as permissions; until then only the owner can
+// connect. The file is removed on shutdown.
+func (q *Quick) ListenUnix(path string, mode os.FileMode, handler ...http.Handler) error {
+	ln := inheritedListener("unix")
+	if ln == nil {
+		if fi, err := os.Stat(path); err == nil && fi.Mode()&os.ModeSocket != 0 {
+			if err := os.Remove(path); err != nil {
+				return err
+			}
+		}
+
+		var err error
+		ln, err = listenUnixSocket(path, mode)
+		if err != nil {
+			return err
+		}
+	}
+
+	q.track(ln)
+	return q.serveListener(context.Background(), ln, handler...)
+}
+
+// ActivationListeners returns the listeners passed to the process through
+// the LISTEN_FDS protocol used by systemd socket activation, in the order
+// they were passed. When LISTEN_PID is set it has to match this process.
+// The LISTEN_* variables are unset so child processes do not inherit them.
+// Without LISTEN_FDS it returns no listeners and no error.
+func ActivationListeners() ([]net.Listener, error) {
+	fds := os.Getenv("LISTEN_FDS")
+	if len(fds) == 0 {
+		return nil, nil
+	}
+
+	if pid := os.Getenv("LISTEN_PID"); len(pid) > 0 && pid != strconv.Itoa(os.Getpid()) {
+		return nil, nil
+	}
+
+	n, err := strconv.Atoi(fds)
+	if err != nil || n < 0 {
+		return nil, errors.New("quick: invalid LISTEN_FDS " + fds)
+	}
+
+	names := strings.Split(os.Getenv("LISTEN_FDNAMES"), ":")
+
+	os.Unsetenv("LISTEN_PID")
+	os.Unsetenv("LISTEN_FDS")
+	os.Unsetenv("LISTEN_FDNAMES")
+
+	listeners := make([]net.Listener, 0, n)
+	for i := 0; i < n; i++ {
+		name := "LISTEN_FD_" + strconv.Itoa(listenFdsStart+i)
+		if i < len(names) && len(names[i]) > 0 {
+			name = names[i]
+		}
+
+		f := os.NewFile(uintptr(listenFdsStart+i), name)
+		// FileListener dups the descriptor, the original is closed either way
+		ln, err := net.FileListener(f)
+		f.Close()
+		if err != nil {
+			for _, l := range listeners {
+				l.Close()
+			}
+			return nil, err
+		}
+		listeners = append(listeners, ln)
+	}
+	return listeners, nil
+}
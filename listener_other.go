@@ -0,0 +1,20 @@
+//go:build !unix
+
+package quick
+
+import (
+	"net"
+	"os"
+)
+
+func listenUnixSocket(path string, mode os.FileMode) (net.Listener, error) {
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(path, mode); err != nil {
+		ln.Close()
+		return nil, err
+	}
+	return ln, nil
+}
@@ -0,0 +1,168 @@
+package quick
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+)
+
+// cover     -> go test -v -count=1 -cover -failfast -run ^TestQuick_Serve$
+// coverHTML -> go test -v -count=1 -failfast -cover -coverprofile=coverage.out -run ^TestQuick_Serve$; go tool cover -html=coverage.out
+func TestQuick_Serve(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+
+	q := New()
+	q.Get("/ping", func(c *Ctx) error { return c.SendString("pong") })
+
+	errc := make(chan error, 1)
+	go func() { errc <- q.Serve(ln) }()
+
+	resp, err := http.Get("http://" + ln.Addr().String() + "/ping")
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	b, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if string(b) != "pong" {
+		t.Errorf("was suppose to return pong and %s come", b)
+	}
+
+	q.Shutdown(context.Background())
+	if err := <-errc; err != http.ErrServerClosed {
+		t.Errorf("Serve() error = %v, want http.ErrServerClosed", err)
+	}
+}
+
+func TestQuick_ListenUnix(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("unix sockets")
+	}
+
+	path := filepath.Join(t.TempDir(), "quick.sock")
+
+	// a stale socket from a previous run must not block the new one
+	stale, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	stale.(*net.UnixListener).SetUnlinkOnClose(false)
+	stale.Close()
+
+	q := New()
+	q.Get("/ping", func(c *Ctx) error { return c.SendString("pong") })
+
+	errc := make(chan error, 1)
+	go func() { errc <- q.ListenUnix(path, 0660) }()
+
+	client := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, "unix", path)
+		},
+	}}
+
+	var resp *http.Response
+	for i := 0; i < 100; i++ {
+		resp, err = client.Get("http://unix/ping")
+		if err == nil {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	b, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if string(b) != "pong" {
+		t.Errorf("was suppose to return pong and %s come", b)
+	}
+
+	fi, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	if fi.Mode().Perm() != 0660 {
+		t.Errorf("socket mode = %v, want 0660", fi.Mode().Perm())
+	}
+
+	q.Shutdown(context.Background())
+	<-errc
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("socket file should be removed on shutdown")
+	}
+}
+
+func TestActivationListeners(t *testing.T) {
+	if os.Getenv("QUICK_TEST_ACTIVATION") == "1" {
+		listeners, err := ActivationListeners()
+		if err != nil || len(listeners) != 1 {
+			os.Exit(2)
+		}
+		if len(os.Getenv("LISTEN_FDS")) > 0 {
+			os.Exit(3)
+		}
+		q := New()
+		q.Get("/ping", func(c *Ctx) error { return c.SendString("activated") })
+		q.Serve(listeners[0])
+		os.Exit(0)
+	}
+
+	if runtime.GOOS == "windows" {
+		t.Skip("descriptor passing")
+	}
+
+	listeners, err := ActivationListeners()
+	if err != nil || len(listeners) != 0 {
+		t.Errorf("ActivationListeners() without LISTEN_FDS = %v, %v", listeners, err)
+	}
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	defer ln.Close()
+	f, err := ln.(*net.TCPListener).File()
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	defer f.Close()
+
+	cmd := exec.Command(os.Args[0], "-test.run=^TestActivationListeners$")
+	cmd.Env = append(os.Environ(), "QUICK_TEST_ACTIVATION=1", "LISTEN_FDS=1", "LISTEN_FDNAMES=http")
+	cmd.ExtraFiles = []*os.File{f}
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	defer cmd.Process.Kill()
+
+	// the parent copy keeps accepting too, close it so the child gets the request
+	ln.Close()
+
+	var resp *http.Response
+	for i := 0; i < 200; i++ {
+		resp, err = http.Get("http://" + ln.Addr().String() + "/ping")
+		if err == nil {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	b, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if string(b) != "activated" {
+		t.Errorf("was suppose to return activated and %s come", b)
+	}
+}
@@ -0,0 +1,61 @@
+//go:build unix
+
+package quick
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+)
+
+// listenUnixSocket creates the socket in a new directory only the owner
+// can enter, applies mode and only then moves it to path, so the socket
+// is never reachable with looser permissions.
+func listenUnixSocket(path string, mode os.FileMode) (net.Listener, error) {
+	dir, err := os.MkdirTemp(filepath.Dir(path), ".quick-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(dir)
+
+	tmp := filepath.Join(dir, "s")
+	ln, err := net.ListenUnix("unix", &net.UnixAddr{Name: tmp, Net: "unix"})
+	if err != nil {
+		return nil, err
+	}
+	// the listener would unlink tmp, the file to remove is path
+	ln.SetUnlinkOnClose(false)
+
+	if err := os.Chmod(tmp, mode); err != nil {
+		ln.Close()
+		os.Remove(tmp)
+		return nil, err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		ln.Close()
+		os.Remove(tmp)
+		return nil, err
+	}
+	return &unixListener{UnixListener: ln, path: path, unlink: true}, nil
+}
+
+// unixListener removes the socket file at path on Close, like
+// net.UnixListener does for the name it was created with.
+type unixListener struct {
+	*net.UnixListener
+	path   string
+	unlink bool
+}
+
+// SetUnlinkOnClose sets whether Close removes the socket file.
+func (l *unixListener) SetUnlinkOnClose(unlink bool) {
+	l.unlink = unlink
+}
+
+func (l *unixListener) Close() error {
+	err := l.UnixListener.Close()
+	if l.unlink {
+		os.Remove(l.path)
+	}
+	return err
+}
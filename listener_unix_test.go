@@ -0,0 +1,40 @@
+//go:build unix
+
+package quick
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// cover -> go test -v -count=1 -cover -failfast -run ^TestListenUnixSocket$
+func TestListenUnixSocket(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "quick.sock")
+	ln, err := listenUnixSocket(path, 0660)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+
+	fi, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	if fi.Mode()&os.ModeSocket == 0 || fi.Mode().Perm() != 0660 {
+		t.Errorf("was suppose to create a socket with mode 0660 and %v come", fi.Mode())
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("was suppose to leave only the socket in %s and %d entries come", dir, len(entries))
+	}
+
+	ln.Close()
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("was suppose to remove the socket on Close and %v come", err)
+	}
+}
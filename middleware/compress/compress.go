@@ -0,0 +1,350 @@
+package compress
+
+import (
+	"bufio"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+	"github.com/jeffotoni/quick"
+	"github.com/klauspost/compress/zstd"
+)
+
+// Level is the compression level, mapped to the scale of each encoding.
+type Level int
+
+const (
+	LevelDefault Level = iota
+	LevelBestSpeed
+	LevelBestCompression
+)
+
+type Config struct {
+	// Level trades CPU for size.
+	// Default: LevelDefault
+	Level Level
+	// MinLength is the smallest body compressed, in bytes. Smaller bodies
+	// cost more to compress than they save. Set 1 to compress them all.
+	// Default: 1024
+	MinLength int
+	// Encodings are offered in this order of preference when the client
+	// accepts several with the same q-value. Supported: br, zstd, gzip and
+	// deflate.
+	// Default: br, zstd, gzip, deflate
+	Encodings []string
+	// ExcludedTypes are media types left alone because they are already
+	// compressed. An entry ending in "/" matches the whole type, e.g.
+	// "video/".
+	// Default: images other than SVG, audio, video, fonts and archives
+	ExcludedTypes []string
+	// Next skips the middleware for the requests it returns true for.
+	// Default: nil
+	Next func(r *http.Request) bool
+}
+
+var ConfigDefault = Config{
+	Level:     LevelDefault,
+	MinLength: 1024,
+	Encodings: []string{"br", "zstd", "gzip", "deflate"},
+	ExcludedTypes: []string{
+		"image/", "audio/", "video/", "font/woff", "font/woff2",
+		"application/zip", "application/gzip", "application/x-gzip",
+		"application/zstd", "application/x-brotli", "application/x-bzip2",
+		"application/x-xz", "application/x-7z-compressed", "application/x-rar-compressed",
+	},
+}
+
+// New returns a middleware that compresses responses with the encoding
+// the Accept-Encoding header prefers. Responses that already carry a
+// Content-Encoding, partial responses and bodies below MinLength are sent
+// as they are. Flushing a response, e.g. for server-sent events, flushes
+// the compressed stream too.
+func New(config ...Config) func(http.Handler) http.Handler {
+	cfd := ConfigDefault
+	if len(config) > 0 {
+		cfd = config[0]
+		if cfd.MinLength == 0 {
+			cfd.MinLength = ConfigDefault.MinLength
+		}
+		if cfd.Encodings == nil {
+			cfd.Encodings = ConfigDefault.Encodings
+		}
+		if cfd.ExcludedTypes == nil {
+			cfd.ExcludedTypes = ConfigDefault.ExcludedTypes
+		}
+	}
+
+	offers := make([]string, len(cfd.Encodings))
+	pools := make(map[string]*sync.Pool, len(cfd.Encodings))
+	for i, name := range cfd.Encodings {
+		name = strings.ToLower(name)
+		offers[i] = name
+		newEncoder, ok := encoders[name]
+		if !ok {
+			panic(fmt.Sprintf("compress: unsupported encoding %q", name))
+		}
+		level := cfd.Level
+		pools[name] = &sync.Pool{New: func() any { return newEncoder(level) }}
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if cfd.Next != nil && cfd.Next(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			addVary(w.Header())
+			encoding := quick.NegotiateEncoding(r.Header.Get("Accept-Encoding"), offers...)
+			if len(encoding) == 0 || r.Method == http.MethodHead {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			cw := &compressWriter{
+				ResponseWriter: w,
+				config:         &cfd,
+				encoding:       encoding,
+				pool:           pools[encoding],
+			}
+			next.ServeHTTP(cw, r)
+			cw.close()
+		})
+	}
+}
+
+// encoder is what gzip, flate, brotli and zstd writers have in common.
+type encoder interface {
+	io.WriteCloser
+	Flush() error
+	Reset(io.Writer)
+}
+
+var encoders = map[string]func(Level) encoder{
+	"gzip": func(l Level) encoder {
+		w, _ := gzip.NewWriterLevel(nil, flateLevel(l))
+		return w
+	},
+	// HTTP deflate is the zlib format, not raw deflate
+	"deflate": func(l Level) encoder {
+		w, _ := zlib.NewWriterLevel(nil, flateLevel(l))
+		return w
+	},
+	"br": func(l Level) encoder {
+		level := brotli.DefaultCompression
+		switch l {
+		case LevelBestSpeed:
+			level = brotli.BestSpeed
+		case LevelBestCompression:
+			level = brotli.BestCompression
+		}
+		return brotli.NewWriterLevel(nil, level)
+	},
+	"zstd": func(l Level) encoder {
+		level := zstd.SpeedDefault
+		switch l {
+		case LevelBestSpeed:
+			level = zstd.SpeedFastest
+		case LevelBestCompression:
+			level = zstd.SpeedBestCompression
+		}
+		// a small window keeps the memory of pooled encoders down
+		w, _ := zstd.NewWriter(nil, zstd.WithEncoderLevel(level), zstd.WithEncoderConcurrency(1), zstd.WithWindowSize(1<<20))
+		return w
+	},
+}
+
+func flateLevel(l Level) int {
+	switch l {
+	case LevelBestSpeed:
+		return flate.BestSpeed
+	case LevelBestCompression:
+		return flate.BestCompression
+	}
+	return flate.DefaultCompression
+}
+
+func addVary(h http.Header) {
+	for _, v := range h.Values("Vary") {
+		for _, field := range strings.Split(v, ",") {
+			if f := strings.TrimSpace(field); f == "*" || strings.EqualFold(f, "Accept-Encoding") {
+				return
+			}
+		}
+	}
+	h.Add("Vary", "Accept-Encoding")
+}
+
+// compressWriter holds the status and the first MinLength bytes back
+// until it knows whether the response is worth compressing.
+type compressWriter struct {
+	http.ResponseWriter
+	config   *Config
+	encoding string
+	pool     *sync.Pool
+	enc      encoder
+	buf      []byte
+	status   int
+	decided  bool
+}
+
+func (w *compressWriter) WriteHeader(code int) {
+	if w.decided {
+		w.ResponseWriter.WriteHeader(code)
+		return
+	}
+	// informational responses like 103 Early Hints go out right away
+	if code >= 100 && code < 200 && code != http.StatusSwitchingProtocols {
+		w.ResponseWriter.WriteHeader(code)
+		return
+	}
+	if w.status != 0 {
+		return
+	}
+	w.status = code
+
+	switch {
+	case code == http.StatusNoContent || code == http.StatusNotModified || code == http.StatusSwitchingProtocols:
+		w.decide(false)
+	case len(w.Header().Get("Content-Length")) > 0:
+		n, _ := strconv.Atoi(w.Header().Get("Content-Length"))
+		w.decide(n >= w.config.MinLength)
+	}
+}
+
+func (w *compressWriter) Write(b []byte) (int, error) {
+	if !w.decided {
+		if w.status == 0 {
+			w.status = http.StatusOK
+		}
+		w.buf = append(w.buf, b...)
+		if len(w.buf) < w.config.MinLength {
+			return len(b), nil
+		}
+		if err := w.decide(true); err != nil {
+			return 0, err
+		}
+		return len(b), nil
+	}
+
+	if w.enc != nil {
+		return w.enc.Write(b)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// decide sends the header and the buffered bytes, compressed when large
+// is true and the response allows it.
+func (w *compressWriter) decide(large bool) error {
+	w.decided = true
+	h := w.Header()
+
+	if large && w.compressible() {
+		if len(h.Get("Content-Type")) == 0 {
+			// net/http would sniff the compressed bytes instead
+			h.Set("Content-Type", http.DetectContentType(w.buf))
+		}
+		h.Del("Content-Length")
+		h.Del("Accept-Ranges")
+		h.Set("Content-Encoding", w.encoding)
+		if etag := h.Get("ETag"); len(etag) > 0 && !strings.HasPrefix(etag, "W/") {
+			h.Set("ETag", "W/"+etag)
+		}
+
+		w.enc = w.pool.Get().(encoder)
+		w.enc.Reset(w.ResponseWriter)
+	}
+
+	if w.status != 0 {
+		w.ResponseWriter.WriteHeader(w.status)
+	}
+	if len(w.buf) == 0 {
+		return nil
+	}
+
+	var err error
+	if w.enc != nil {
+		_, err = w.enc.Write(w.buf)
+	} else {
+		_, err = w.ResponseWriter.Write(w.buf)
+	}
+	w.buf = nil
+	return err
+}
+
+func (w *compressWriter) compressible() bool {
+	h := w.Header()
+	if len(h.Get("Content-Encoding")) > 0 || len(h.Get("Content-Range")) > 0 ||
+		w.status == http.StatusPartialContent || w.status < http.StatusOK {
+		return false
+	}
+	if strings.Contains(h.Get("Cache-Control"), "no-transform") {
+		return false
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(h.Get("Content-Type"))
+	if len(mediaType) == 0 {
+		return true
+	}
+	for _, excluded := range w.config.ExcludedTypes {
+		if mediaType == excluded || (strings.HasSuffix(excluded, "/") && strings.HasPrefix(mediaType, excluded) && mediaType != "image/svg+xml") {
+			return false
+		}
+	}
+	return true
+}
+
+// Flush sends what was written so far, compressing it when the response
+// qualifies even if it is still below MinLength, since more is coming.
+func (w *compressWriter) Flush() {
+	if !w.decided {
+		if w.status == 0 {
+			w.status = http.StatusOK
+		}
+		w.decide(true)
+	}
+	if w.enc != nil {
+		w.enc.Flush()
+	}
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack lets websockets through, the middleware has nothing to finish.
+func (w *compressWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hj, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, http.ErrNotSupported
+	}
+	w.decided = true
+	return hj.Hijack()
+}
+
+// Unwrap gives http.ResponseController the original writer.
+func (w *compressWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// close sends a body that stayed below MinLength as it is, or ends the
+// compressed stream.
+func (w *compressWriter) close() {
+	if !w.decided {
+		w.decide(false)
+	}
+	if w.enc != nil {
+		w.enc.Close()
+		w.enc.Reset(io.Discard)
+		w.pool.Put(w.enc)
+		w.enc = nil
+	}
+}
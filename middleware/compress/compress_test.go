@@ -0,0 +1,257 @@
+package compress
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/jeffotoni/quick"
+	"github.com/klauspost/compress/zstd"
+)
+
+func decode(t *testing.T, encoding string, body []byte) string {
+	t.Helper()
+
+	var r io.Reader
+	var err error
+	switch encoding {
+	case "gzip":
+		r, err = gzip.NewReader(bytes.NewReader(body))
+	case "deflate":
+		r, err = zlib.NewReader(bytes.NewReader(body))
+	case "br":
+		r = brotli.NewReader(bytes.NewReader(body))
+	case "zstd":
+		var d *zstd.Decoder
+		d, err = zstd.NewReader(bytes.NewReader(body))
+		r = d
+	default:
+		return string(body)
+	}
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	b, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("%s: error: %v", encoding, err)
+	}
+	return string(b)
+}
+
+// go test -v -count=1 -failfast -run ^TestNew$
+func TestNew(t *testing.T) {
+	large := strings.Repeat(`{"name":"jeff","lang":"go"},`, 100)
+
+	type args struct {
+		config         Config
+		acceptEncoding string
+		contentType    string
+		body           string
+		wantEncoding   string
+	}
+
+	tests := []struct {
+		name string
+		args args
+	}{
+		{
+			name: "gzip",
+			args: args{acceptEncoding: "gzip", body: large, wantEncoding: "gzip"},
+		},
+		{
+			name: "deflate",
+			args: args{acceptEncoding: "deflate", body: large, wantEncoding: "deflate"},
+		},
+		{
+			name: "brotli",
+			args: args{acceptEncoding: "gzip, deflate, br", body: large, wantEncoding: "br"},
+		},
+		{
+			name: "zstd",
+			args: args{acceptEncoding: "zstd", body: large, wantEncoding: "zstd"},
+		},
+		{
+			name: "q_values",
+			args: args{acceptEncoding: "br;q=0.5, gzip;q=0.8", body: large, wantEncoding: "gzip"},
+		},
+		{
+			name: "wildcard",
+			args: args{acceptEncoding: "*, br;q=0", body: large, wantEncoding: "zstd"},
+		},
+		{
+			name: "server_preference",
+			args: args{config: Config{Encodings: []string{"GZIP", "br"}}, acceptEncoding: "br, gzip", body: large, wantEncoding: "gzip"},
+		},
+		{
+			name: "best_speed",
+			args: args{config: Config{Level: LevelBestSpeed}, acceptEncoding: "gzip", body: large, wantEncoding: "gzip"},
+		},
+		{
+			name: "best_compression",
+			args: args{config: Config{Level: LevelBestCompression}, acceptEncoding: "zstd", body: large, wantEncoding: "zstd"},
+		},
+		{
+			name: "not_accepted",
+			args: args{acceptEncoding: "identity", body: large},
+		},
+		{
+			name: "q_after_other_params",
+			args: args{acceptEncoding: "gzip;foo=1;q=0", body: large},
+		},
+		{
+			name: "no_accept_encoding",
+			args: args{body: large},
+		},
+		{
+			name: "small_body",
+			args: args{acceptEncoding: "gzip", body: `{"name":"jeff"}`},
+		},
+		{
+			name: "min_length",
+			args: args{config: Config{MinLength: 1}, acceptEncoding: "gzip", body: `{"name":"jeff"}`, wantEncoding: "gzip"},
+		},
+		{
+			name: "already_compressed",
+			args: args{acceptEncoding: "gzip", contentType: "image/png", body: large},
+		},
+		{
+			name: "svg",
+			args: args{acceptEncoding: "gzip", contentType: "image/svg+xml", body: large, wantEncoding: "gzip"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := quick.New()
+			q.Use(New(tt.args.config))
+			q.Get("/data", func(c *quick.Ctx) error {
+				if len(tt.args.contentType) > 0 {
+					c.Set("Content-Type", tt.args.contentType)
+				}
+				return c.Status(200).String(tt.args.body)
+			})
+
+			req := httptest.NewRequest("GET", "/data", nil)
+			req.Header.Set("Accept-Encoding", tt.args.acceptEncoding)
+			rec := httptest.NewRecorder()
+			q.ServeHTTP(rec, req)
+
+			if rec.Code != 200 {
+				t.Fatalf("was suppose to return 200 and %d come", rec.Code)
+			}
+			if got := rec.Header().Get("Content-Encoding"); got != tt.args.wantEncoding {
+				t.Errorf("was suppose to return Content-Encoding %q and %q come", tt.args.wantEncoding, got)
+			}
+			if got := rec.Header().Get("Vary"); got != "Accept-Encoding" {
+				t.Errorf("was suppose to return Vary Accept-Encoding and %q come", got)
+			}
+			if len(tt.args.wantEncoding) > 0 && rec.Body.Len() >= len(tt.args.body) && len(tt.args.body) > 100 {
+				t.Errorf("was suppose to shrink the body and %d bytes come", rec.Body.Len())
+			}
+			if got := decode(t, tt.args.wantEncoding, rec.Body.Bytes()); got != tt.args.body {
+				t.Errorf("was suppose to return the body and %q come", got)
+			}
+		})
+	}
+}
+
+func TestNew_Headers(t *testing.T) {
+	body := strings.Repeat("quick ", 500)
+	h := New()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/etag":
+			w.Header().Set("ETag", `"abc"`)
+			w.Header().Set("Content-Length", "3000")
+			w.Header().Set("Accept-Ranges", "bytes")
+		case "/encoded":
+			w.Header().Set("Content-Encoding", "gzip")
+		case "/range":
+			w.Header().Set("Content-Range", "bytes 0-2999/6000")
+			w.WriteHeader(http.StatusPartialContent)
+		case "/empty":
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		io.WriteString(w, body)
+	}))
+
+	serve := func(path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", path, nil)
+		req.Header.Set("Accept-Encoding", "gzip")
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := serve("/etag")
+	if rec.Header().Get("Content-Encoding") != "gzip" || rec.Header().Get("ETag") != `W/"abc"` ||
+		len(rec.Header().Get("Content-Length")) > 0 || len(rec.Header().Get("Accept-Ranges")) > 0 {
+		t.Errorf("was suppose to weaken the ETag and drop Content-Length and Accept-Ranges and %v come", rec.Header())
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "text/plain; charset=utf-8" {
+		t.Errorf("was suppose to sniff the uncompressed body and %q come", ct)
+	}
+
+	rec = serve("/encoded")
+	if rec.Header().Get("Content-Encoding") != "gzip" || rec.Body.String() != body {
+		t.Errorf("was suppose to leave an encoded body alone")
+	}
+
+	rec = serve("/range")
+	if rec.Code != http.StatusPartialContent || len(rec.Header().Get("Content-Encoding")) > 0 {
+		t.Errorf("was suppose to leave a partial response alone and %d %v come", rec.Code, rec.Header())
+	}
+
+	rec = serve("/empty")
+	if rec.Code != http.StatusNoContent || rec.Body.Len() > 0 || len(rec.Header().Get("Content-Encoding")) > 0 {
+		t.Errorf("was suppose to return 204 without body and %d %q come", rec.Code, rec.Body.String())
+	}
+}
+
+func TestNew_Flush(t *testing.T) {
+	flushed := make(chan struct{})
+	h := New()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		io.WriteString(w, "data: one\n\n")
+		w.(http.Flusher).Flush()
+		<-flushed
+		io.WriteString(w, "data: two\n\n")
+	}))
+
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+
+	req, _ := http.NewRequest("GET", srv.URL, nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	res, err := http.DefaultTransport.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	defer res.Body.Close()
+
+	if res.Header.Get("Content-Encoding") != "gzip" {
+		t.Fatalf("was suppose to compress the stream and %v come", res.Header)
+	}
+	zr, err := gzip.NewReader(res.Body)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+
+	// the first event must arrive before the handler writes the second
+	first := make([]byte, len("data: one\n\n"))
+	if _, err := io.ReadFull(zr, first); err != nil || string(first) != "data: one\n\n" {
+		t.Fatalf("was suppose to read the flushed event and %q %v come", first, err)
+	}
+	close(flushed)
+
+	rest, err := io.ReadAll(zr)
+	if err != nil || string(rest) != "data: two\n\n" {
+		t.Errorf("was suppose to read the second event and %q %v come", rest, err)
+	}
+}
@@ -0,0 +1,68 @@
+package recover
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/jeffotoni/quick"
+)
+
+type Config struct {
+	// EnableStackTrace logs the panic value and its stack trace
+	// with the standard log package.
+	// Default: false
+	EnableStackTrace bool
+	// OnPanic is called with the request, the panic value and the stack
+	// trace before the 500 is sent, e.g. to report the crash.
+	// Default: nil
+	OnPanic func(r *http.Request, v any, stack []byte)
+}
+
+var ConfigDefault = Config{}
+
+// New returns a middleware that turns panics into 500 responses sent
+// through the ErrorHandler configured on quick.New. The client gets
+// quick.ErrInternalServerError, the panic is kept as its wrapped error.
+func New(config ...Config) func(http.Handler) http.Handler {
+	cfd := ConfigDefault
+	if len(config) > 0 {
+		cfd = config[0]
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer func() {
+				v := recover()
+				if v == nil {
+					return
+				}
+				if v == http.ErrAbortHandler {
+					panic(v)
+				}
+
+				var stack []byte
+				if cfd.EnableStackTrace || cfd.OnPanic != nil {
+					stack = debug.Stack()
+				}
+				if cfd.EnableStackTrace {
+					log.Printf("panic: %v\n%s", v, stack)
+				}
+				if cfd.OnPanic != nil {
+					cfd.OnPanic(r, v, stack)
+				}
+
+				quick.HandleError(w, r, quick.ErrInternalServerError.Wrap(panicError(v)))
+			}()
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func panicError(v any) error {
+	if err, ok := v.(error); ok {
+		return fmt.Errorf("panic: %w", err)
+	}
+	return errors.New(fmt.Sprint("panic: ", v))
+}
@@ -0,0 +1,121 @@
+package recover
+
+import (
+	"bytes"
+	"errors"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/jeffotoni/quick"
+)
+
+// go test -v -count=1 -failfast -run ^TestNew$
+func TestNew(t *testing.T) {
+	type args struct {
+		config     Config
+		quick      quick.Config
+		reqHeaders map[string]string
+		wantCode   int
+		wantOut    string
+	}
+
+	tests := []struct {
+		name string
+		args args
+	}{
+		{
+			name: "default",
+			args: args{wantCode: 500, wantOut: "Internal Server Error"},
+		},
+		{
+			name: "json_from_accept",
+			args: args{
+				reqHeaders: map[string]string{"Accept": "application/json"},
+				wantCode:   500,
+				wantOut:    `{"code":500,"message":"Internal Server Error"}`,
+			},
+		},
+		{
+			name: "custom_error_handler",
+			args: args{
+				quick: quick.Config{
+					ErrorHandler: func(c *quick.Ctx, err error) {
+						c.Status(503).SendString(err.Error())
+					},
+				},
+				wantCode: 503,
+				wantOut:  "Internal Server Error: panic: something broke",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := quick.New(tt.args.quick)
+			q.Use(New(tt.args.config))
+			q.Get("/panic", func(c *quick.Ctx) error {
+				panic("something broke")
+			})
+
+			data, err := q.QuickTest("GET", "/panic", tt.args.reqHeaders)
+			if err != nil {
+				t.Errorf("error: %v", err)
+				return
+			}
+
+			if data.StatusCode() != tt.args.wantCode {
+				t.Errorf("was suppose to return %d and %d come", tt.args.wantCode, data.StatusCode())
+			}
+
+			if data.BodyStr() != tt.args.wantOut {
+				t.Errorf("was suppose to return %s and %s come", tt.args.wantOut, data.BodyStr())
+			}
+		})
+	}
+}
+
+func TestNew_OnPanic(t *testing.T) {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+
+	boom := errors.New("boom")
+	var gotValue any
+	var gotStack []byte
+	var gotPath string
+
+	q := quick.New()
+	q.Use(New(Config{
+		EnableStackTrace: true,
+		OnPanic: func(r *http.Request, v any, stack []byte) {
+			gotPath = r.URL.Path
+			gotValue = v
+			gotStack = stack
+		},
+	}))
+	q.Post("/v1/user/:id", func(c *quick.Ctx) error {
+		panic(boom)
+	})
+
+	data, err := q.QuickTest("POST", "/v1/user/1", nil)
+	if err != nil {
+		t.Errorf("error: %v", err)
+		return
+	}
+
+	if data.StatusCode() != 500 {
+		t.Errorf("was suppose to return 500 and %d come", data.StatusCode())
+	}
+	if gotValue != boom || gotPath != "/v1/user/1" {
+		t.Errorf("OnPanic got %v for %s", gotValue, gotPath)
+	}
+	if !bytes.Contains(gotStack, []byte("recover_test.go")) {
+		t.Errorf("stack trace should point at the panicking handler:\n%s", gotStack)
+	}
+	if !strings.Contains(buf.String(), "panic: boom") {
+		t.Errorf("stack trace was not logged: %s", buf.String())
+	}
+}
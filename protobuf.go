@@ -0,0 +1,74 @@
+package quick
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	ContentTypeProtoBuf    = `application/x-protobuf`
+	ContentTypeAppProtoBuf = `application/protobuf`
+)
+
+// protoCodec reads and writes proto.Message values in the binary wire
+// format.
+type protoCodec struct{}
+
+func (protoCodec) Decode(r io.Reader, v any) error {
+	m, ok := v.(proto.Message)
+	if !ok {
+		return fmt.Errorf("quick: protobuf body needs a proto.Message, not %T", v)
+	}
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	return proto.Unmarshal(b, m)
+}
+
+func (protoCodec) Encode(w io.Writer, v any) error {
+	m, ok := v.(proto.Message)
+	if !ok {
+		return fmt.Errorf("quick: cannot encode %T as protobuf", v)
+	}
+	b, err := proto.Marshal(m)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(b)
+	return err
+}
+
+// decodeProtoJSON reads a JSON body into a proto.Message with protojson,
+// which knows the field names and well-known types encoding/json does not.
+func decodeProtoJSON(r io.Reader, m proto.Message) error {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	return protojson.Unmarshal(b, m)
+}
+
+func encodeProtoJSON(w io.Writer, m proto.Message) error {
+	b, err := protojson.Marshal(m)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(b)
+	return err
+}
+
+// ProtoBuf writes msg in the protobuf wire format. Use Encode to answer
+// protobuf or JSON, whichever the Accept header prefers.
+func (c *Ctx) ProtoBuf(msg proto.Message) error {
+	var buf bytes.Buffer
+	if err := c.codec(ContentTypeProtoBuf).Encode(&buf, msg); err != nil {
+		return err
+	}
+	c.Response.Header().Set("Content-Type", ContentTypeProtoBuf)
+	return c.writeResponse(buf.Bytes())
+}
@@ -0,0 +1,127 @@
+package quick
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// cover     -> go test -v -count=1 -cover -failfast -run ^TestCtx_ProtoBuf$
+// coverHTML -> go test -v -count=1 -failfast -cover -coverprofile=coverage.out -run ^TestCtx_ProtoBuf$; go tool cover -html=coverage.out
+func TestCtx_ProtoBuf(t *testing.T) {
+	user, err := structpb.NewStruct(map[string]any{"name": "jeff", "age": 35})
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	wire, err := proto.Marshal(user)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+
+	q := New()
+	q.Post("/v1/user", func(c *Ctx) error {
+		var msg structpb.Struct
+		if err := c.Bind(&msg); err != nil {
+			return err
+		}
+		return c.Status(201).Encode(&msg)
+	})
+	q.Get("/v1/user", func(c *Ctx) error {
+		return c.ProtoBuf(user)
+	})
+
+	decode := func(t *testing.T, b []byte) *structpb.Struct {
+		t.Helper()
+		var got structpb.Struct
+		if err := proto.Unmarshal(b, &got); err != nil {
+			t.Fatalf("error: %v", err)
+		}
+		return &got
+	}
+
+	t.Run("protobuf", func(t *testing.T) {
+		data, _ := q.QuickTest("GET", "/v1/user", nil)
+		if data.StatusCode() != 200 || data.Response().Header.Get("Content-Type") != ContentTypeProtoBuf {
+			t.Fatalf("was suppose to return 200 %s and %d %s come", ContentTypeProtoBuf, data.StatusCode(), data.Response().Header.Get("Content-Type"))
+		}
+		if got := decode(t, data.Body()); !proto.Equal(got, user) {
+			t.Errorf("was suppose to return %v and %v come", user, got)
+		}
+	})
+
+	t.Run("protobuf_to_protobuf", func(t *testing.T) {
+		headers := map[string]string{"Content-Type": ContentTypeProtoBuf, "Accept": ContentTypeProtoBuf}
+		data, _ := q.QuickTest("POST", "/v1/user", headers, wire)
+		if data.StatusCode() != 201 || data.Response().Header.Get("Content-Type") != ContentTypeProtoBuf {
+			t.Fatalf("was suppose to return 201 %s and %d %s come", ContentTypeProtoBuf, data.StatusCode(), data.Response().Header.Get("Content-Type"))
+		}
+		if got := decode(t, data.Body()); !proto.Equal(got, user) {
+			t.Errorf("was suppose to return %v and %v come", user, got)
+		}
+	})
+
+	t.Run("json_to_protobuf", func(t *testing.T) {
+		headers := map[string]string{"Content-Type": "application/json; charset=utf-8", "Accept": ContentTypeAppProtoBuf}
+		data, _ := q.QuickTest("POST", "/v1/user", headers, []byte(`{"name":"jeff","age":35}`))
+		if data.StatusCode() != 201 {
+			t.Fatalf("was suppose to return 201 and %d %s come", data.StatusCode(), data.BodyStr())
+		}
+		if got := decode(t, data.Body()); !proto.Equal(got, user) {
+			t.Errorf("was suppose to return %v and %v come", user, got)
+		}
+	})
+
+	t.Run("protobuf_to_json", func(t *testing.T) {
+		headers := map[string]string{"Content-Type": ContentTypeProtoBuf, "Accept": "application/json"}
+		data, _ := q.QuickTest("POST", "/v1/user", headers, wire)
+		if data.StatusCode() != 201 || data.Response().Header.Get("Content-Type") != ContentTypeAppJSON {
+			t.Fatalf("was suppose to return 201 JSON and %d %s come", data.StatusCode(), data.Response().Header.Get("Content-Type"))
+		}
+		// protojson output is not stable, compare the messages
+		var got structpb.Struct
+		if err := protojson.Unmarshal(data.Body(), &got); err != nil {
+			t.Fatalf("error: %v %s", err, data.BodyStr())
+		}
+		if !proto.Equal(&got, user) {
+			t.Errorf("was suppose to return %v and %v come", user, &got)
+		}
+	})
+
+	t.Run("invalid_body", func(t *testing.T) {
+		headers := map[string]string{"Content-Type": ContentTypeProtoBuf}
+		data, _ := q.QuickTest("POST", "/v1/user", headers, []byte{0xff, 0xff})
+		if data.StatusCode() != 400 {
+			t.Errorf("was suppose to return 400 for an invalid message and %d come", data.StatusCode())
+		}
+	})
+}
+
+// cover -> go test -v -count=1 -cover -failfast -run ^TestCtx_EncodeNotProtoBuf$
+func TestCtx_EncodeNotProtoBuf(t *testing.T) {
+	type user struct {
+		Name string `json:"name"`
+	}
+
+	q := New()
+	q.Get("/v1/user", func(c *Ctx) error {
+		return c.Encode(user{Name: "jeff"})
+	})
+
+	tests := []struct {
+		accept string
+		code   int
+		ctype  string
+	}{
+		{accept: ContentTypeProtoBuf + ", application/json;q=0.5", code: 200, ctype: ContentTypeAppJSON},
+		{accept: ContentTypeProtoBuf, code: 406, ctype: "text/plain; charset=utf-8"},
+		{accept: "application/*", code: 200, ctype: ContentTypeAppJSON},
+	}
+	for _, tt := range tests {
+		data, _ := q.QuickTest("GET", "/v1/user", map[string]string{"Accept": tt.accept})
+		if data.StatusCode() != tt.code || data.Response().Header.Get("Content-Type") != tt.ctype {
+			t.Errorf("%q: was suppose to return %d %s and %d %s come", tt.accept, tt.code, tt.ctype, data.StatusCode(), data.Response().Header.Get("Content-Type"))
+		}
+	}
+}
@@ -3,16 +3,19 @@ package quick
 import (
 	"bytes"
 	"context"
-	"encoding/json"
-	"encoding/xml"
+	"crypto/tls"
+	"errors"
 	"io"
+	"log"
+	"mime"
+	"mime/multipart"
+	"net"
 	"net/http"
-	"regexp"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
-
-	"github.com/jeffotoni/quick/internal/concat"
-	p "github.com/jeffotoni/quick/internal/print"
 )
 
 const (
@@ -21,7 +24,7 @@ const (
 	ContentTypeTextXML = `text/xml`
 )
 
-type HandleFunc func(*quickCtx.Ctx) error
+type HandleFunc func(*Ctx) error
 
 type Route struct {
 	//Pattern *regexp.Regexp
@@ -38,6 +41,7 @@ type ctxServeHttp struct {
 	Params    string
 	Method    string
 	ParamsMap map[string]string
+	quick     *Quick
 }
 
 type Config struct {
@@ -47,6 +51,48 @@ type Config struct {
 	WriteTimeout      time.Duration
 	IdleTimeout       time.Duration
 	ReadHeaderTimeout time.Duration
+	// DecompressBody decodes request bodies sent with Content-Encoding
+	// gzip, deflate or zstd before the handler reads them. MaxBodySize
+	// then limits the decompressed size too, so small compressed bodies
+	// cannot expand without bound. Other encodings are answered with 415.
+	DecompressBody bool
+	// ErrorLog, ConnState and BaseContext are handed to the http.Server
+	// as they are, see its documentation.
+	ErrorLog    *log.Logger
+	ConnState   func(net.Conn, http.ConnState)
+	BaseContext func(net.Listener) context.Context
+	// DisableKeepAlives closes every connection after its response.
+	DisableKeepAlives bool
+	// H2C serves cleartext HTTP/2, both with prior knowledge and through
+	// "Upgrade: h2c", next to HTTP/1.1. See ListenH2C.
+	H2C bool
+	// TLSConfig is used by ListenTLS, cloned so the certificate reload
+	// and HTTP/2 setup never change the caller's value.
+	TLSConfig *tls.Config
+	// RedirectHTTPAddr makes ListenTLS also listen for plain HTTP on this
+	// address and answer every request with a redirect to HTTPS.
+	RedirectHTTPAddr string
+	// AutoOptions answers OPTIONS requests for paths without an Options
+	// route with 204 and the Allow header listing the registered methods.
+	AutoOptions bool
+	// HandleSignals shuts the server down gracefully on SIGINT and SIGTERM.
+	HandleSignals bool
+	// GracefulRestart calls Restart on SIGHUP, handing the listening
+	// sockets to a new process of the same binary.
+	GracefulRestart bool
+	// RestartTimeout bounds how long Restart waits for the new process
+	// to serve before it kills it and keeps this one running.
+	// Default: 30s
+	RestartTimeout time.Duration
+	// ShutdownTimeout bounds how long a graceful shutdown started by
+	// HandleSignals or ListenWithContext waits for open requests.
+	// Default: 10s
+	ShutdownTimeout time.Duration
+	// ErrorHandler receives every error returned by a HandleFunc, for
+	// routes, groups, NotFound and MethodNotAllowed handlers alike.
+	// Default: *HTTPError values are sent with their status code, any other
+	// error becomes a 500 with the error text as text/plain.
+	ErrorHandler func(*Ctx, error)
 }
 
 var defaultConfig = Config{
@@ -56,6 +102,8 @@ var defaultConfig = Config{
 	//WriteTimeout: 10 * time.Second,
 	//IdleTimeout:       1 * time.Second,
 	ReadHeaderTimeout: time.Duration(3) * time.Second,
+	ShutdownTimeout:   time.Duration(10) * time.Second,
+	RestartTimeout:    time.Duration(30) * time.Second,
 }
 
 type Quick struct {
@@ -66,6 +114,19 @@ type Quick struct {
 	mux         *http.ServeMux
 	routes      []Route
 	mws2        []any
+	router      router
+	notFound    HandleFunc
+	notAllowed  HandleFunc
+	server      atomic.Value
+	redirect    atomic.Value
+	listeners   atomic.Value
+	stop        atomic.Value
+	onStart     []func()
+	onShutdown  []func()
+	validators  map[string]ValidatorFunc
+	rules       *sync.Map
+	codecs      map[string]Codec
+	codecTypes  []string
 	CorsSet     func(http.Handler) http.Handler
 	CorsOptions map[string]string
 }
@@ -82,9 +143,23 @@ func New(c ...Config) *Quick {
 		mux:     http.NewServeMux(),
 		handler: http.NewServeMux(),
 		config:  config,
+		rules:   new(sync.Map),
 	}
 }
 
+// NotFound sets the handler for requests that match no route.
+// The Ctx status starts as 404.
+func (q *Quick) NotFound(handlerFunc HandleFunc) {
+	q.notFound = handlerFunc
+}
+
+// MethodNotAllowed sets the handler for requests whose path has routes
+// for other methods only. The Allow header is already set and the Ctx
+// status starts as 405.
+func (q *Quick) MethodNotAllowed(handlerFunc HandleFunc) {
+	q.notAllowed = handlerFunc
+}
+
 func (q *Quick) Use(mw any, nf ...string) {
 	if len(nf) > 0 {
 		if strings.ToLower(nf[0]) == "cors" {
@@ -102,52 +177,113 @@ func (q *Quick) Use(mw any, nf ...string) {
 	q.mws2 = append(q.mws2, mw)
 }
 
-func (q *Quick) Get(pattern string, handlerFunc HandleFunc) {
-	path, params, partternExist := extractParamsPattern(pattern)
+// methods is the list of methods registered by Any.
+var methods = []string{
+	http.MethodGet,
+	http.MethodHead,
+	http.MethodPost,
+	http.MethodPut,
+	http.MethodPatch,
+	http.MethodDelete,
+	http.MethodConnect,
+	http.MethodOptions,
+	http.MethodTrace,
+}
 
-	route := Route{
-		Pattern: partternExist,
-		Path:    path,
-		Params:  params,
-		handler: extractParamsGet(path, params, handlerFunc),
-		Method:  http.MethodGet,
-	}
+func (q *Quick) Get(pattern string, handlerFunc HandleFunc, opts ...RouteConfig) {
+	q.handle(http.MethodGet, "", pattern, handlerFunc, opts)
+}
 
-	q.appendRoute(&route)
-	q.mux.HandleFunc(path, route.handler)
+func (q *Quick) Post(pattern string, handlerFunc HandleFunc, opts ...RouteConfig) {
+	q.handle(http.MethodPost, "", pattern, handlerFunc, opts)
 }
 
-func (q *Quick) Post(pattern string, handlerFunc HandleFunc) {
-	_, params, partternExist := extractParamsPattern(pattern)
-	pathPost := concat.String("post#", pattern)
+func (q *Quick) Put(pattern string, handlerFunc HandleFunc, opts ...RouteConfig) {
+	q.handle(http.MethodPut, "", pattern, handlerFunc, opts)
+}
 
-	route := Route{
-		Pattern: partternExist,
-		Params:  params,
-		Path:    pattern,
-		handler: extractParamsPost(q, pattern, handlerFunc),
-		Method:  http.MethodPost,
-	}
+func (q *Quick) Patch(pattern string, handlerFunc HandleFunc, opts ...RouteConfig) {
+	q.handle(http.MethodPatch, "", pattern, handlerFunc, opts)
+}
 
-	q.appendRoute(&route)
-	q.mux.HandleFunc(pathPost, route.handler)
+func (q *Quick) Delete(pattern string, handlerFunc HandleFunc, opts ...RouteConfig) {
+	q.handle(http.MethodDelete, "", pattern, handlerFunc, opts)
+}
+
+func (q *Quick) Options(pattern string, handlerFunc HandleFunc, opts ...RouteConfig) {
+	q.handle(http.MethodOptions, "", pattern, handlerFunc, opts)
+}
+
+// Head registers a HEAD route. GET routes already answer HEAD requests
+// without a body, so Head is only needed to override that behaviour.
+func (q *Quick) Head(pattern string, handlerFunc HandleFunc, opts ...RouteConfig) {
+	q.handle(http.MethodHead, "", pattern, handlerFunc, opts)
 }
 
-func (q *Quick) Put(pattern string, handlerFunc HandleFunc) {
-	_, params, partternExist := extractParamsPattern(pattern)
+func (q *Quick) Connect(pattern string, handlerFunc HandleFunc, opts ...RouteConfig) {
+	q.handle(http.MethodConnect, "", pattern, handlerFunc, opts)
+}
 
-	pathPut := concat.String("put#", pattern)
+func (q *Quick) Trace(pattern string, handlerFunc HandleFunc, opts ...RouteConfig) {
+	q.handle(http.MethodTrace, "", pattern, handlerFunc, opts)
+}
+
+// Any registers the handler for every standard HTTP method.
+func (q *Quick) Any(pattern string, handlerFunc HandleFunc, opts ...RouteConfig) {
+	q.Match(methods, pattern, handlerFunc, opts...)
+}
+
+// Match registers the handler for each method in the list.
+func (q *Quick) Match(methods []string, pattern string, handlerFunc HandleFunc, opts ...RouteConfig) {
+	for _, method := range methods {
+		q.handle(strings.ToUpper(method), "", pattern, handlerFunc, opts)
+	}
+}
+
+// RouteConfig tunes a single route, passed as the last argument of Get,
+// Post and the other registration methods.
+type RouteConfig struct {
+	// MaxBodySize replaces Config.MaxBodySize for the route, -1 lifts
+	// the limit for handlers that apply their own.
+	MaxBodySize int64
+	// NoBuffer never keeps the body in memory: Body is empty, Bind and
+	// BodyParser decode straight from the stream and large uploads are
+	// read through BodyReader. The stream is read once, so a second Bind
+	// fails with 500.
+	NoBuffer bool
+	// DecompressBody turns on Config.DecompressBody for the route.
+	DecompressBody bool
+}
+
+// handle builds the Route for method and pattern and adds it to the router.
+// GET and HEAD routes do not check Content-Length against MaxBodySize
+// before the handler runs, every other method does.
+func (q *Quick) handle(method, group, pattern string, handlerFunc HandleFunc, opts []RouteConfig) {
+	var config RouteConfig
+	if len(opts) > 0 {
+		config = opts[0]
+	}
+	config.DecompressBody = config.DecompressBody || q.config.DecompressBody
+
+	path, params, partternExist := extractParamsPattern(pattern)
 
 	route := Route{
 		Pattern: partternExist,
 		Path:    pattern,
-		handler: extractParamsPut(q, pattern, handlerFunc),
-		Method:  http.MethodPut,
 		Params:  params,
+		Method:  method,
+		Group:   group,
+	}
+
+	switch method {
+	case http.MethodGet, http.MethodHead:
+		route.Path = path
+		route.handler = extractParamsGet(q, path, params, handlerFunc, config)
+	default:
+		route.handler = extractParamsBody(q, pattern, handlerFunc, config)
 	}
 
 	q.appendRoute(&route)
-	q.mux.HandleFunc(pathPut, route.handler)
 }
 
 func extractHeaders(req http.Request) map[string][]string {
@@ -158,22 +294,62 @@ func extractHeaders(req http.Request) map[string][]string {
 	return headersMap
 }
 
+func extractQuery(req *http.Request) map[string]string {
+	querys := make(map[string]string)
+	queryParams := req.URL.Query()
+	for key, values := range queryParams {
+		querys[key] = values[0]
+	}
+	return querys
+}
+
+// extractBind decodes the body by its Content-Type: forms through their
+// form tags, anything else through the registered codec.
 func extractBind(c *Ctx, v interface{}) (err error) {
-	var req http.Request = *c.Request
-	if strings.ToLower(req.Header.Get("Content-Type")) == ContentTypeAppJSON ||
-		strings.ToLower(req.Header.Get("Content-Type")) == "application/json; charset=utf-8" ||
-		strings.ToLower(req.Header.Get("Content-Type")) == "application/json;charset=utf-8" {
-		err = json.NewDecoder(bytes.NewReader(c.bodyByte)).Decode(v)
-	} else if strings.ToLower(req.Header.Get("Content-Type")) == ContentTypeTextXML ||
-		strings.ToLower(req.Header.Get("Content-Type")) == ContentTypeAppXML {
-		err = xml.NewDecoder(bytes.NewReader(c.bodyByte)).Decode(v)
+	mediaType, _, _ := mime.ParseMediaType(c.Request.Header.Get("Content-Type"))
+	if mediaType == ContentTypeFormURLEncoded || mediaType == ContentTypeMultipartForm {
+		return bindForm(c, v)
 	}
-	return err
+	return bindCodec(c, mediaType, v)
+}
+
+// errBodyConsumed is returned by Bind once the stream of a NoBuffer
+// route was decoded, a mistake in the handler rather than the request.
+var errBodyConsumed = ErrInternalServerError.Wrap(errors.New("quick: body of a NoBuffer route already read"))
+
+// decodeBody runs decode on the buffered body, or on the stream for
+// NoBuffer routes.
+func decodeBody(c *Ctx, decode func(io.Reader) error) error {
+	if c.noBuffer && !c.bodyRead {
+		err := decode(c.BodyReader())
+		c.bodyRead, c.bodyErr = true, errBodyConsumed
+		return bodyError(err)
+	}
+	if err := c.readBody(); err != nil {
+		return err
+	}
+	return decode(bytes.NewReader(c.bodyByte))
 }
 
+// extractParamsPattern splits pattern at its first :param. The colon of
+// a named regex segment like {id:[0-9]+} belongs to the segment.
 func extractParamsPattern(pattern string) (path, params, partternExist string) {
 	path = pattern
-	index := strings.Index(pattern, ":")
+	index, depth := -1, 0
+	for i := 0; i < len(pattern) && index < 0; i++ {
+		switch pattern[i] {
+		case '{':
+			depth++
+		case '}':
+			if depth > 0 {
+				depth--
+			}
+		case ':':
+			if depth == 0 {
+				index = i
+			}
+		}
+	}
 
 	if index > 0 {
 		path = pattern[:index]
@@ -188,7 +364,8 @@ func extractParamsPattern(pattern string) (path, params, partternExist string) {
 	return
 }
 
-func extractParamsPost(q *Quick, pathTmp string, handlerFunc HandleFunc) http.HandlerFunc {
+func extractParamsBody(q *Quick, pathTmp string, handlerFunc HandleFunc, config RouteConfig) http.HandlerFunc {
+	maxBodySize := q.maxBodySize(config)
 	return func(w http.ResponseWriter, req *http.Request) {
 		v := req.Context().Value(0)
 		if v == nil {
@@ -196,67 +373,113 @@ func extractParamsPost(q *Quick, pathTmp string, handlerFunc HandleFunc) http.Ha
 			return
 		}
 
-		if req.ContentLength > q.config.MaxBodySize {
+		if maxBodySize > 0 && req.ContentLength > maxBodySize {
 			http.Error(w, "Request body too large", http.StatusRequestEntityTooLarge)
 			return
 		}
 
-		headersMap := extractHeaders(*req)
-
-		c := &Ctx{
-			Response: w,
-			Request:  req,
-			bodyByte: extractBodyBytes(req.Body),
-			Headers:  headersMap,
-		}
-		execHandleFunc(c, handlerFunc)
+		c := newRouteCtx(w, req, v.(ctxServeHttp), maxBodySize, config)
+		q.execHandleFunc(c, handlerFunc)
+		c.removeForm()
 	}
 }
 
-func extractParamsPut(q *Quick, pathTmp string, handlerFunc HandleFunc) http.HandlerFunc {
-	return func(w http.ResponseWriter, req *http.Request) {
-		v := req.Context().Value(0)
-		if v == nil {
-			http.NotFound(w, req)
-			return
+// newRouteCtx builds the Ctx of a route. The body is limited to
+// maxBodySize but only read when the handler asks for it. A compressed
+// body is limited before and after decoding.
+func newRouteCtx(w http.ResponseWriter, req *http.Request, cval ctxServeHttp, maxBodySize int64, config RouteConfig) *Ctx {
+	if maxBodySize > 0 && req.Body != nil {
+		req.Body = http.MaxBytesReader(w, req.Body, maxBodySize)
+	}
+	if config.DecompressBody && req.Body != nil && len(req.Header.Get("Content-Encoding")) > 0 {
+		decompressBody(req)
+		if maxBodySize > 0 {
+			req.Body = http.MaxBytesReader(w, req.Body, maxBodySize)
 		}
+	}
 
-		if req.ContentLength > q.config.MaxBodySize {
-			http.Error(w, "Request body too large", http.StatusRequestEntityTooLarge)
-			return
-		}
+	return &Ctx{
+		Response: w,
+		Request:  req,
+		Headers:  extractHeaders(*req),
+		Params:   cval.ParamsMap,
+		Query:    extractQuery(req),
+		noBuffer: config.NoBuffer,
+		maxBody:  maxBodySize,
+		quick:    cval.quick,
+	}
+}
 
-		headersMap := extractHeaders(*req)
+// maxBodySize is the body limit of a route, 0 when there is none.
+func (q *Quick) maxBodySize(config RouteConfig) int64 {
+	switch {
+	case config.MaxBodySize < 0:
+		return 0
+	case config.MaxBodySize > 0:
+		return config.MaxBodySize
+	case q.config.MaxBodySize > 0:
+		return q.config.MaxBodySize
+	}
+	return defaultConfig.MaxBodySize
+}
 
-		cval := v.(ctxServeHttp)
+func (q *Quick) execHandleFunc(c *Ctx, handleFunc HandleFunc) {
+	err := handleFunc(c)
+	if err != nil {
+		q.handleError(c, err)
+	}
+}
 
-		c := &Ctx{
-			Response: w,
-			Request:  req,
-			Headers:  headersMap,
-			bodyByte: extractBodyBytes(req.Body),
-			Params:   cval.ParamsMap,
-		}
+// HandleError answers req with err through the ErrorHandler of the Quick
+// serving it, so middlewares reply in the same format as the routes.
+// Outside a Quick route the default error handler is used.
+func HandleError(w http.ResponseWriter, req *http.Request, err error) {
+	c := &Ctx{
+		Response: w,
+		Request:  req,
+		Headers:  extractHeaders(*req),
+	}
 
-		execHandleFunc(c, handlerFunc)
+	if v, ok := req.Context().Value(0).(ctxServeHttp); ok && v.quick != nil {
+		c.Params = v.ParamsMap
+		c.quick = v.quick
+		v.quick.handleError(c, err)
+		return
 	}
+	defaultErrorHandler(c, err)
 }
 
-func execHandleFunc(c *Ctx, handleFunc HandleFunc) {
-	err := handleFunc(c)
-	if err != nil {
-		c.Set("Content-Type", "text/plain; charset=utf-8")
-		c.Status(500).SendString(err.Error())
+func (q *Quick) handleError(c *Ctx, err error) {
+	if q.config.ErrorHandler != nil {
+		q.config.ErrorHandler(c, err)
+		return
 	}
+	defaultErrorHandler(c, err)
 }
 
-func extractBodyBytes(r io.ReadCloser) []byte {
-	b, err := io.ReadAll(r)
+// readBody buffers the body once. NoBuffer routes never buffer it.
+func (c *Ctx) readBody() error {
+	if c.bodyRead || c.noBuffer || c.Request == nil || c.Request.Body == nil {
+		return c.bodyErr
+	}
+
+	c.bodyRead = true
+	b, err := io.ReadAll(c.Request.Body)
 	if err != nil {
-		return nil
+		c.bodyErr = bodyError(err)
+		return c.bodyErr
 	}
+	c.bodyByte = b
+	return nil
+}
 
-	return b
+// bodyError turns a body over the MaxBodySize limit into a 413.
+func bodyError(err error) error {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) || errors.Is(err, multipart.ErrMessageTooLarge) {
+		return ErrRequestTooLarge.Wrap(err)
+	}
+	return err
 }
 
 func (c *Ctx) Param(key string) string {
@@ -284,40 +507,55 @@ func (q *Quick) mwWrapper(handler http.Handler) http.Handler {
 func (q *Quick) appendRoute(route *Route) {
 	route.handler = q.mwWrapper(route.handler).ServeHTTP
 	q.routes = append(q.routes, *route)
+
+	pattern := route.Pattern
+	if len(pattern) == 0 {
+		pattern = route.Path
+	}
+	q.router.add(route.Method, pattern, route.handler)
 }
 
+// Bind decodes the body into v by its Content-Type and validates it,
+// see Validate.
 func (c *Ctx) Bind(v interface{}) (err error) {
-	return extractBind(c, v)
+	if err = extractBind(c, v); err != nil {
+		return err
+	}
+	return c.Validate(v)
 }
 
 func (c *Ctx) BodyParser(v interface{}) (err error) {
-	if strings.Contains(c.Request.Header.Get("Content-Type"), ContentTypeAppJSON) {
-		err = json.Unmarshal(c.bodyByte, v)
-		if err != nil {
-			return err
-		}
-	}
-
-	if strings.Contains(c.Request.Header.Get("Content-Type"), ContentTypeTextXML) ||
-		strings.Contains(c.Request.Header.Get("Content-Type"), ContentTypeAppXML) {
-		err = xml.Unmarshal(c.bodyByte, v)
-		if err != nil {
-			return err
-		}
+	if err = extractBind(c, v); err != nil {
+		return err
 	}
-
-	return nil
+	return c.Validate(v)
 }
 
+// Body returns the request body, read on the first call. It is nil when
+// reading fails, e.g. over MaxBodySize, and on NoBuffer routes; Bind and
+// BodyReader report those errors.
 func (c *Ctx) Body() []byte {
+	c.readBody()
 	return c.bodyByte
 }
 
 func (c *Ctx) BodyString() string {
-	return string(c.bodyByte)
+	return string(c.Body())
 }
 
-func extractParamsGet(pathTmp, paramsPath string, handlerFunc HandleFunc) http.HandlerFunc {
+// BodyReader returns the request body as a stream, so large uploads are
+// handled without keeping them in memory. Reading past MaxBodySize fails
+// with an error the error handler answers with 413. Once Body or Bind
+// buffered the body, BodyReader reads the buffered copy.
+func (c *Ctx) BodyReader() io.Reader {
+	if c.bodyRead || c.Request == nil || c.Request.Body == nil {
+		return bytes.NewReader(c.bodyByte)
+	}
+	return c.Request.Body
+}
+
+func extractParamsGet(q *Quick, pathTmp, paramsPath string, handlerFunc HandleFunc, config RouteConfig) http.HandlerFunc {
+	maxBodySize := q.maxBodySize(config)
 	return func(w http.ResponseWriter, req *http.Request) {
 		v := req.Context().Value(0)
 		if v == nil {
@@ -325,104 +563,105 @@ func extractParamsGet(pathTmp, paramsPath string, handlerFunc HandleFunc) http.H
 			return
 		}
 
-		cval := v.(ctxServeHttp)
-		querys := make(map[string]string)
-		queryParams := req.URL.Query()
-		for key, values := range queryParams {
-			querys[key] = values[0]
-		}
-		headersMap := extractHeaders(*req)
-
-		c := &Ctx{
-			Response: w,
-			Request:  req,
-			Params:   cval.ParamsMap,
-			Query:    querys,
-			bodyByte: extractBodyBytes(req.Body),
-			Headers:  headersMap,
-		}
-		execHandleFunc(c, handlerFunc)
+		c := newRouteCtx(w, req, v.(ctxServeHttp), maxBodySize, config)
+		q.execHandleFunc(c, handlerFunc)
+		c.removeForm()
 	}
 }
 
 func (q *Quick) ServeHTTP(w http.ResponseWriter, req *http.Request) {
-	for i := 0; i < len(q.routes); i++ {
-		var requestURI = req.URL.Path
-		var patternUri = q.routes[i].Pattern
-
-		if q.routes[i].Method != req.Method {
-			continue
-		}
-
-		if len(patternUri) == 0 {
-			patternUri = q.routes[i].Path
-		}
-
-		paramsMap, isValid := createParamsAndValid(requestURI, patternUri)
-
-		if !isValid {
-			continue
+	handler, paramsMap := q.router.lookup(req.Method, req.URL.Path)
+	if req.Method == http.MethodHead {
+		if handler == nil {
+			handler, paramsMap = q.router.lookup(http.MethodGet, req.URL.Path)
 		}
+		hw := &headResponseWriter{ResponseWriter: w}
+		q.serveRoute(hw, req, handler, paramsMap)
+		hw.finish()
+		return
+	}
+	q.serveRoute(w, req, handler, paramsMap)
+}
 
-		var c = ctxServeHttp{Path: requestURI, ParamsMap: paramsMap, Method: q.routes[i].Method}
-		req = req.WithContext(context.WithValue(req.Context(), 0, c))
-		q.routes[i].handler(w, req)
+func (q *Quick) serveRoute(w http.ResponseWriter, req *http.Request, handler http.HandlerFunc, paramsMap map[string]string) {
+	if handler == nil {
+		q.notMatched(w, req)
 		return
 	}
 
-	http.NotFound(w, req)
+	var c = ctxServeHttp{Path: req.URL.Path, ParamsMap: paramsMap, Method: req.Method, quick: q}
+	req = req.WithContext(context.WithValue(req.Context(), 0, c))
+	handler(w, req)
 }
 
-func createParamsAndValid(reqURI, patternURI string) (map[string]string, bool) {
-	params := make(map[string]string)
-	var tmpPath string
-
-	reqURISplt := strings.Split(reqURI, "/")
-	patternURISplt := strings.Split(patternURI, "/")
+// notMatched answers requests without a route for their method.
+// When the path exists for other methods it replies 405 with the Allow
+// header, or 204 for OPTIONS when Config.AutoOptions is set.
+func (q *Quick) notMatched(w http.ResponseWriter, req *http.Request) {
+	allow := q.router.allowed(req.URL.Path)
+	if len(allow) == 0 {
+		if q.notFound != nil {
+			q.execHandleFunc(q.newStatusCtx(w, req, http.StatusNotFound), q.notFound)
+			return
+		}
+		http.NotFound(w, req)
+		return
+	}
 
-	if len(reqURISplt) != len(patternURISplt) {
-		return nil, false
+	if q.config.AutoOptions && !isAllowed(allow, http.MethodOptions) {
+		allow = append(allow, http.MethodOptions)
 	}
+	w.Header().Set("Allow", strings.Join(allow, ", "))
 
-	for pttrn := 0; pttrn < len(patternURISplt); pttrn++ { // collecting params
-		if strings.Contains(patternURISplt[pttrn], ":") {
-			params[patternURISplt[pttrn][1:]] = reqURISplt[pttrn]
-			tmpPath = concat.String(tmpPath, "/", reqURISplt[pttrn])
-		} else if strings.Contains(patternURISplt[pttrn], "{") { // regex support
-			regexPattern := patternURISplt[pttrn][1:]
-			regexPattern = regexPattern[:len(regexPattern)-1]
-			rgx := regexp.MustCompile(regexPattern)
-			params[patternURISplt[pttrn]] = rgx.FindString(reqURISplt[pttrn])
-			tmpPath = concat.String(tmpPath, "/", rgx.FindString(reqURISplt[pttrn]))
-		} else {
-			tmpPath = concat.String(tmpPath, "/", patternURISplt[pttrn])
-		}
+	if q.config.AutoOptions && req.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusNoContent)
+		return
 	}
 
-	// This tmpPath is to check if request's uri is the same that our pattern
-	if tmpPath[1:] != reqURI {
-		return nil, false
+	if q.notAllowed != nil {
+		q.execHandleFunc(q.newStatusCtx(w, req, http.StatusMethodNotAllowed), q.notAllowed)
+		return
+	}
+	http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+}
+
+// newStatusCtx builds the Ctx handed to NotFound and MethodNotAllowed handlers.
+func (q *Quick) newStatusCtx(w http.ResponseWriter, req *http.Request, status int) *Ctx {
+	return &Ctx{
+		Response:  w,
+		Request:   req,
+		Headers:   extractHeaders(*req),
+		Query:     extractQuery(req),
+		resStatus: status,
+		quick:     q,
 	}
+}
 
-	return params, true
+func isAllowed(allow []string, method string) bool {
+	for _, m := range allow {
+		if m == method {
+			return true
+		}
+	}
+	return false
 }
 
 func (c *Ctx) JSON(v interface{}) error {
-	b, err := json.Marshal(v)
-	if err != nil {
+	var buf bytes.Buffer
+	if err := c.codec(ContentTypeAppJSON).Encode(&buf, v); err != nil {
 		return err
 	}
 	c.Response.Header().Set("Content-Type", ContentTypeAppJSON)
-	return c.writeResponse(b)
+	return c.writeResponse(buf.Bytes())
 }
 
 func (c *Ctx) XML(v interface{}) error {
-	b, err := xml.Marshal(v)
-	if err != nil {
+	var buf bytes.Buffer
+	if err := c.codec(ContentTypeTextXML).Encode(&buf, v); err != nil {
 		return err
 	}
 	c.Response.Header().Set("Content-Type", ContentTypeTextXML)
-	return c.writeResponse(b)
+	return c.writeResponse(buf.Bytes())
 }
 
 func (c *Ctx) writeResponse(b []byte) error {
@@ -433,6 +672,47 @@ func (c *Ctx) writeResponse(b []byte) error {
 	return err
 }
 
+// headResponseWriter drops the body so GET handlers can answer HEAD
+// requests. It holds the status back and counts the body, so finish can
+// send the Content-Length the GET response would have.
+type headResponseWriter struct {
+	http.ResponseWriter
+	status int
+	size   int64
+}
+
+func (w *headResponseWriter) WriteHeader(code int) {
+	// informational responses go out as they come
+	if code >= 100 && code < 200 && code != http.StatusSwitchingProtocols {
+		w.ResponseWriter.WriteHeader(code)
+		return
+	}
+	if w.status == 0 {
+		w.status = code
+	}
+}
+
+func (w *headResponseWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	w.size += int64(len(b))
+	return len(b), nil
+}
+
+// finish sends the header, with Content-Length set to the body size
+// unless the handler set its own.
+func (w *headResponseWriter) finish() {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	header := w.Header()
+	if w.size > 0 && len(header.Get("Content-Length")) == 0 && len(header.Get("Transfer-Encoding")) == 0 {
+		header.Set("Content-Length", strconv.FormatInt(w.size, 10))
+	}
+	w.ResponseWriter.WriteHeader(w.status)
+}
+
 func (c *Ctx) Byte(b []byte) (err error) {
 	return c.writeResponse(b)
 }
@@ -446,11 +726,6 @@ func (c *Ctx) String(s string) error {
 	return c.writeResponse([]byte(s))
 }
 
-func (c *Ctx) SendFile(file []byte) error {
-	_, err := c.Response.Write(file)
-	return err
-}
-
 func (c *Ctx) Set(key, value string) {
 	c.Response.Header().Set(key, value)
 }
@@ -459,11 +734,6 @@ func (c *Ctx) Append(key, value string) {
 	c.Response.Header().Add(key, value)
 }
 
-func (c *Ctx) Accepts(acceptType string) *Ctx {
-	c.Response.Header().Set("Accept", acceptType)
-	return c
-}
-
 func (c *Ctx) Status(status int) *Ctx {
 	c.resStatus = status
 	return c
@@ -473,28 +743,6 @@ func (q *Quick) GetRoute() []Route {
 	return q.routes
 }
 
-func (q *Quick) Static(staticFolder string) {
-	// generate route get with a pattern like this: /static/:file
-	// pattern := concat.String(staticFolder, ":file")
-	// q.Get(pattern, func(c *Ctx) {
-	// 	path, _, _ := extractParamsPattern(pattern)
-	// 	file := c.Params["file"]
-	// 	filePath := concat.String(".", path, "/", file)
-	// 	err := qos.FileExist(filePath)
-	// 	if err != nil {
-	// 		c.Status(http.StatusForbidden).SendString(err.Error())
-	// 		return
-	// 	}
-
-	// 	fileBytes, err := os.ReadFile(filePath)
-	// 	if err != nil {
-	// 		c.Status(http.StatusInternalServerError).SendString(err.Error())
-	// 		return
-	// 	}
-	// 	c.Status(http.StatusOK).SendFile(fileBytes)
-	// })
-}
-
 func (q *Quick) execHandler(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		next.ServeHTTP(w, r)
@@ -506,46 +754,34 @@ func (q *Quick) corsHandler() http.Handler {
 }
 
 func (q *Quick) httpServer(addr string, handler ...http.Handler) *http.Server {
-	var server *http.Server
+	var h http.Handler = q
 
 	if len(handler) > 0 {
 		//assume o nosso mux
-		server = &http.Server{
-			Addr:    addr,
-			Handler: q.execHandler(handler[0]),
-			// ReadTimeout:
-			// WriteTimeout:
-			// MaxHeaderBytes:
-			// IdleTimeout:
-			ReadHeaderTimeout: q.config.ReadHeaderTimeout,
-		}
-
+		h = q.execHandler(handler[0])
 	} else if q.Cors {
-		server = &http.Server{
-			Addr:    addr,
-			Handler: q.corsHandler(),
-			// ReadTimeout:
-			// WriteTimeout:
-			// MaxHeaderBytes:
-			// IdleTimeout:
-			ReadHeaderTimeout: q.config.ReadHeaderTimeout,
-		}
-	} else {
-		server = &http.Server{
-			Addr:    addr,
-			Handler: q,
-			// ReadTimeout:
-			// WriteTimeout:
-			// MaxHeaderBytes:
-			// IdleTimeout:
-			ReadHeaderTimeout: q.config.ReadHeaderTimeout,
-		}
+		h = q.corsHandler()
+	}
+
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           h,
+		ReadTimeout:       q.config.ReadTimeout,
+		WriteTimeout:      q.config.WriteTimeout,
+		IdleTimeout:       q.config.IdleTimeout,
+		ReadHeaderTimeout: q.config.ReadHeaderTimeout,
+		MaxHeaderBytes:    int(q.config.MaxHeaderBytes),
+		ErrorLog:          q.config.ErrorLog,
+		ConnState:         q.config.ConnState,
+		BaseContext:       q.config.BaseContext,
 	}
+	server.SetKeepAlivesEnabled(!q.config.DisableKeepAlives)
 	return server
 }
 
+// Listen serves on addr until the server fails or Shutdown is called,
+// in which case it returns http.ErrServerClosed. With Config.HandleSignals
+// SIGINT and SIGTERM shut the server down gracefully and Listen returns nil.
 func (q *Quick) Listen(addr string, handler ...http.Handler) error {
-	server := q.httpServer(addr, handler...)
-	p.Stdout("\033[0;33mRun Server Quick:", addr, "\033[0m\n")
-	return server.ListenAndServe()
+	return q.ListenWithContext(context.Background(), addr, handler...)
 }
@@ -3,7 +3,6 @@ package quick
 import (
 	"bytes"
 	"errors"
-	"github.com/jeffotoni/quick/context"
 	"io"
 	"net/http/httptest"
 	"strings"
@@ -18,18 +17,18 @@ type (
 	}
 
 	quickMockCtxJSON struct {
-		Ctx    *quickCtx.Ctx
+		Ctx    *Ctx
 		Params map[string]string
 	}
 
 	quickMockCtxXML struct {
-		Ctx         *quickCtx.Ctx
+		Ctx         *Ctx
 		Params      map[string]string
 		ContentType string
 	}
 )
 
-func QuickMockCtxJSON(ctx *quickCtx.Ctx, params map[string]string) QuickMockCtx {
+func QuickMockCtxJSON(ctx *Ctx, params map[string]string) QuickMockCtx {
 	return &quickMockCtxJSON{
 		Ctx:    ctx,
 		Params: params,
@@ -78,6 +77,7 @@ func (m quickMockCtxJSON) Post(URI string, body []byte) error {
 	m.Ctx.Request.Header.Set("Content-Type", ContentTypeAppJSON)
 	m.Ctx.Params = m.Params
 	m.Ctx.bodyByte = body
+	m.Ctx.bodyRead = true
 	return nil
 }
 
@@ -91,6 +91,7 @@ func (m quickMockCtxJSON) Put(URI string, body []byte) error {
 	m.Ctx.Request.Header.Set("Content-Type", ContentTypeAppJSON)
 	m.Ctx.Params = m.Params
 	m.Ctx.bodyByte = body
+	m.Ctx.bodyRead = true
 	return nil
 }
 
@@ -152,6 +153,7 @@ func (m quickMockCtxXML) Post(URI string, body []byte) error {
 	m.Ctx.Request.Header.Set("Content-Type", contentT)
 	m.Ctx.Params = m.Params
 	m.Ctx.bodyByte = body
+	m.Ctx.bodyRead = true
 	return nil
 }
 
@@ -171,6 +173,7 @@ func (m quickMockCtxXML) Put(URI string, body []byte) error {
 	m.Ctx.Request.Header.Set("Content-Type", contentT)
 	m.Ctx.Params = m.Params
 	m.Ctx.bodyByte = body
+	m.Ctx.bodyRead = true
 	return nil
 }
 
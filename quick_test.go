@@ -1,13 +1,17 @@
 package quick
 
 import (
+	"bytes"
 	"encoding/json"
 	"encoding/xml"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"reflect"
+	"strconv"
 	"strings"
 	"testing"
 
@@ -412,11 +416,12 @@ func TestQuick_Put(t *testing.T) {
 	}
 }
 
-func Test_extractParamsPost(t *testing.T) {
+func Test_extractParamsBody(t *testing.T) {
 	type args struct {
 		quick       Quick
 		pathTmp     string
 		handlerFunc func(*Ctx) error
+		config      RouteConfig
 	}
 	tests := []struct {
 		name string
@@ -427,8 +432,8 @@ func Test_extractParamsPost(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := extractParamsPost(&tt.args.quick, tt.args.pathTmp, tt.args.handlerFunc); !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("extractParamsPost() = %v, want %v", got, tt.want)
+			if got := extractParamsBody(&tt.args.quick, tt.args.pathTmp, tt.args.handlerFunc, tt.args.config); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("extractParamsBody() = %v, want %v", got, tt.want)
 			}
 		})
 	}
@@ -587,9 +592,11 @@ func TestQuick_ServeStaticFile(t *testing.T) {
 
 func Test_extractParamsGet(t *testing.T) {
 	type args struct {
+		quick       Quick
 		pathTmp     string
 		paramsPath  string
 		handlerFunc func(*Ctx) error
+		config      RouteConfig
 	}
 	tests := []struct {
 		name string
@@ -600,7 +607,7 @@ func Test_extractParamsGet(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := extractParamsGet(tt.args.pathTmp, tt.args.paramsPath, tt.args.handlerFunc); !reflect.DeepEqual(got, tt.want) {
+			if got := extractParamsGet(&tt.args.quick, tt.args.pathTmp, tt.args.paramsPath, tt.args.handlerFunc, tt.args.config); !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("extractParamsGet() = %v, want %v", got, tt.want)
 			}
 		})
@@ -801,41 +808,65 @@ func TestCtx_Set(t *testing.T) {
 }
 
 func TestCtx_Accepts(t *testing.T) {
-	type fields struct {
-		Response http.ResponseWriter
-		Request  *http.Request
-		Headers  map[string][]string
-		Params   map[string]string
-		Query    map[string]string
-		JSON     map[string]interface{}
-		BodyByte []byte
-		JsonStr  string
-	}
 	type args struct {
-		acceptType string
+		accept string
+		offers []string
 	}
 	tests := []struct {
-		name   string
-		fields fields
-		args   args
-		want   *Ctx
+		name string
+		args args
+		want string
 	}{
-		// TODO: Add test cases.
+		{
+			name: "no_header",
+			args: args{accept: "", offers: []string{"application/json", "text/html"}},
+			want: "application/json",
+		},
+		{
+			name: "exact",
+			args: args{accept: "text/html", offers: []string{"application/json", "text/html"}},
+			want: "text/html",
+		},
+		{
+			name: "q_values",
+			args: args{accept: "application/json;q=0.5, text/html;q=0.8", offers: []string{"application/json", "text/html"}},
+			want: "text/html",
+		},
+		{
+			name: "specific_over_wildcard",
+			args: args{accept: "*/*, application/xml", offers: []string{"text/plain", "application/xml"}},
+			want: "application/xml",
+		},
+		{
+			name: "subtype_wildcard",
+			args: args{accept: "text/*;q=0.9, application/json;q=0.1", offers: []string{"application/json", "text/plain"}},
+			want: "text/plain",
+		},
+		{
+			name: "short_names",
+			args: args{accept: "application/xml", offers: []string{"json", "xml"}},
+			want: "xml",
+		},
+		{
+			name: "refused",
+			args: args{accept: "text/html, */*;q=0", offers: []string{"application/json"}},
+			want: "",
+		},
+		{
+			name: "browser",
+			args: args{accept: "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8", offers: []string{"application/json", "application/xml"}},
+			want: "application/xml",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			c := &Ctx{
-				Response: tt.fields.Response,
-				Request:  tt.fields.Request,
-				Headers:  tt.fields.Headers,
-				Params:   tt.fields.Params,
-				Query:    tt.fields.Query,
-				//JSON:     tt.fields.JSON,
-				bodyByte: tt.fields.BodyByte,
-				JsonStr:  tt.fields.JsonStr,
+			req := httptest.NewRequest("GET", "/", nil)
+			if len(tt.args.accept) > 0 {
+				req.Header.Set("Accept", tt.args.accept)
 			}
-			if got := c.Accepts(tt.args.acceptType); !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("Ctx.Accepts() = %v, want %v", got, tt.want)
+			c := &Ctx{Request: req}
+			if got := c.Accepts(tt.args.offers...); got != tt.want {
+				t.Errorf("Ctx.Accepts() = %q, want %q", got, tt.want)
 			}
 		})
 	}
@@ -911,6 +942,27 @@ func TestQuick_GetRoute(t *testing.T) {
 	}
 }
 
+// go test -v -count=1 -failfast -run ^TestQuick_GetRouteRegex$
+func TestQuick_GetRouteRegex(t *testing.T) {
+	q := New()
+	q.Get("/user/{id:[0-9]+}", func(c *Ctx) error { return nil })
+	q.Get("/user/{id:[0-9]+}/posts/:post", func(c *Ctx) error { return nil })
+
+	want := []Route{
+		{Path: "/user/{id:[0-9]+}", Method: "GET"},
+		{Pattern: "/user/{id:[0-9]+}/posts/:post", Path: "/user/{id:[0-9]+}/posts", Params: "/:post", Method: "GET"},
+	}
+	got := q.GetRoute()
+	if len(got) != len(want) {
+		t.Fatalf("was suppose to return %d routes and %d come", len(want), len(got))
+	}
+	for i := range want {
+		if got[i].Pattern != want[i].Pattern || got[i].Path != want[i].Path || got[i].Params != want[i].Params || got[i].Method != want[i].Method {
+			t.Errorf("was suppose to return %+v and %+v come", want[i], got[i])
+		}
+	}
+}
+
 func TestQuick_Listen(t *testing.T) {
 	type fields struct {
 		routes  []Route
@@ -1021,6 +1073,22 @@ func Test_extractParamsPattern(t *testing.T) {
 			wantParams:        "/:param1/:param2/some/:param3",
 			wantPartternExist: "/v1/customer/params/:param1/:param2/some/:param3",
 		},
+		{
+			name: "should keep a named regex segment in the path",
+			args: args{
+				pattern: "/v1/user/{id:[0-9]+}",
+			},
+			wantPath: "/v1/user/{id:[0-9]+}",
+		},
+		{
+			name: "should skip the colon of a named regex segment",
+			args: args{
+				pattern: "/v1/user/{id:[0-9]+}/posts/:post",
+			},
+			wantPath:          "/v1/user/{id:[0-9]+}/posts",
+			wantParams:        "/:post",
+			wantPartternExist: "/v1/user/{id:[0-9]+}/posts/:post",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -1102,3 +1170,364 @@ func TestCtx_Append(t *testing.T) {
 		})
 	}
 }
+
+// cover     -> go test -v -count=1 -cover -failfast -run ^TestQuick_Methods$
+// coverHTML -> go test -v -count=1 -failfast -cover -coverprofile=coverage.out -run ^TestQuick_Methods$; go tool cover -html=coverage.out
+func TestQuick_Methods(t *testing.T) {
+	type args struct {
+		method   string
+		route    string
+		reqBody  []byte
+		wantCode int
+		wantOut  string
+	}
+
+	echo := func(c *Ctx) error {
+		return c.Status(200).SendString(concat.String(c.Request.Method, " ", c.Param("id"), " ", c.BodyString()))
+	}
+
+	r := New()
+	r.Delete("/v1/user/:id", echo)
+	r.Patch("/v1/user/:id", echo)
+	r.Options("/v1/user/:id", echo)
+	r.Connect("/v1/user/:id", echo)
+	r.Trace("/v1/user/:id", echo)
+	r.Post("/v1/user/:id", echo)
+	r.Get("/v1/get/:id", echo)
+	r.Head("/v1/head/:id", func(c *Ctx) error {
+		c.Set("X-Head", "true")
+		return c.Status(204).SendString("dropped")
+	})
+	r.Any("/v1/any", echo)
+	r.Match([]string{"get", "post"}, "/v1/match", echo)
+
+	tests := []struct {
+		name string
+		args args
+	}{
+		{name: "delete", args: args{method: "DELETE", route: "/v1/user/7", wantCode: 200, wantOut: "DELETE 7 "}},
+		{name: "patch_with_body", args: args{method: "PATCH", route: "/v1/user/7", reqBody: []byte(`{"a":1}`), wantCode: 200, wantOut: `PATCH 7 {"a":1}`}},
+		{name: "options", args: args{method: "OPTIONS", route: "/v1/user/7", wantCode: 200, wantOut: "OPTIONS 7 "}},
+		{name: "connect", args: args{method: "CONNECT", route: "/v1/user/7", wantCode: 200, wantOut: "CONNECT 7 "}},
+		{name: "trace", args: args{method: "TRACE", route: "/v1/user/7", wantCode: 200, wantOut: "TRACE 7 "}},
+		{name: "post_with_params", args: args{method: "POST", route: "/v1/user/7", wantCode: 200, wantOut: "POST 7 "}},
+		{name: "head_falls_back_to_get", args: args{method: "HEAD", route: "/v1/get/7", wantCode: 200, wantOut: ""}},
+		{name: "head_route", args: args{method: "HEAD", route: "/v1/head/7", wantCode: 204, wantOut: ""}},
+		{name: "any_put", args: args{method: "PUT", route: "/v1/any", wantCode: 200, wantOut: "PUT  "}},
+		{name: "any_delete", args: args{method: "DELETE", route: "/v1/any", wantCode: 200, wantOut: "DELETE  "}},
+		{name: "match_post", args: args{method: "POST", route: "/v1/match", wantCode: 200, wantOut: "POST  "}},
+		{name: "match_not_listed", args: args{method: "PUT", route: "/v1/match", wantCode: 405, wantOut: "Method Not Allowed\n"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := r.QuickTest(tt.args.method, tt.args.route, nil, tt.args.reqBody)
+			if err != nil {
+				t.Errorf("error: %v", err)
+				return
+			}
+
+			if data.BodyStr() != tt.args.wantOut {
+				t.Errorf("was suppose to return %q and %q come", tt.args.wantOut, data.BodyStr())
+				return
+			}
+
+			if tt.args.wantCode != data.StatusCode() {
+				t.Errorf("was suppose to return %d and %d come", tt.args.wantCode, data.StatusCode())
+			}
+		})
+	}
+}
+
+// cover -> go test -v -count=1 -cover -failfast -run ^TestQuick_HeadContentLength$
+func TestQuick_HeadContentLength(t *testing.T) {
+	big := strings.Repeat("x", 64<<10)
+	q := New()
+	q.Get("/big", func(c *Ctx) error {
+		return c.SendString(big)
+	})
+	q.Get("/sized", func(c *Ctx) error {
+		c.Set("Content-Length", "3")
+		return c.SendString("abc")
+	})
+	q.Get("/empty", func(c *Ctx) error {
+		return c.Status(204).SendString("")
+	})
+
+	srv := httptest.NewServer(q)
+	defer srv.Close()
+
+	tests := []struct {
+		route    string
+		wantCode int
+		wantLen  string
+	}{
+		{route: "/big", wantCode: 200, wantLen: strconv.Itoa(len(big))},
+		{route: "/sized", wantCode: 200, wantLen: "3"},
+		{route: "/empty", wantCode: 204, wantLen: ""},
+	}
+
+	for _, tt := range tests {
+		data, _ := q.QuickTest("HEAD", tt.route, nil)
+		if data.StatusCode() != tt.wantCode || len(data.Body()) != 0 {
+			t.Errorf("%s: was suppose to return %d without body and %d %d bytes come", tt.route, tt.wantCode, data.StatusCode(), len(data.Body()))
+		}
+		if cl := data.Response().Header.Get("Content-Length"); cl != tt.wantLen {
+			t.Errorf("%s: was suppose to return Content-Length %q and %q come", tt.route, tt.wantLen, cl)
+		}
+
+		resp, err := http.Head(srv.URL + tt.route)
+		if err != nil {
+			t.Fatalf("error: %v", err)
+		}
+		resp.Body.Close()
+		if cl := resp.Header.Get("Content-Length"); resp.StatusCode != tt.wantCode || cl != tt.wantLen {
+			t.Errorf("%s: was suppose to return %d Content-Length %q and %d %q come", tt.route, tt.wantCode, tt.wantLen, resp.StatusCode, cl)
+		}
+	}
+}
+
+// cover     -> go test -v -count=1 -cover -failfast -run ^TestQuick_MethodNotAllowed$
+// coverHTML -> go test -v -count=1 -failfast -cover -coverprofile=coverage.out -run ^TestQuick_MethodNotAllowed$; go tool cover -html=coverage.out
+func TestQuick_MethodNotAllowed(t *testing.T) {
+	type args struct {
+		config    Config
+		method    string
+		route     string
+		wantCode  int
+		wantAllow string
+	}
+
+	handler := func(c *Ctx) error {
+		return c.SendString("ok")
+	}
+
+	newApp := func(c Config) *Quick {
+		r := New(c)
+		r.Get("/v1/user/:id", handler)
+		r.Put("/v1/user/:id", handler)
+		r.Delete("/v1/user/{[0-9]+}", handler)
+		r.Match([]string{"PROPFIND"}, "/v1/user/:id", handler)
+		r.Options("/v1/opt", handler)
+		r.Post("/v1/opt", handler)
+		r.Head("/v1/x", handler)
+		r.Get("/v1/y", handler)
+		return r
+	}
+
+	tests := []struct {
+		name string
+		args args
+	}{
+		{
+			name: "wrong_method",
+			args: args{method: "POST", route: "/v1/user/7", wantCode: 405, wantAllow: "GET, HEAD, PUT, DELETE, PROPFIND"},
+		},
+		{
+			name: "regex_only_for_delete",
+			args: args{method: "POST", route: "/v1/user/abc", wantCode: 405, wantAllow: "GET, HEAD, PUT, PROPFIND"},
+		},
+		{
+			name: "options_without_auto",
+			args: args{method: "OPTIONS", route: "/v1/user/7", wantCode: 405, wantAllow: "GET, HEAD, PUT, DELETE, PROPFIND"},
+		},
+		{
+			name: "auto_options",
+			args: args{config: Config{AutoOptions: true}, method: "OPTIONS", route: "/v1/user/7", wantCode: 204, wantAllow: "GET, HEAD, PUT, DELETE, PROPFIND, OPTIONS"},
+		},
+		{
+			name: "auto_options_on_405",
+			args: args{config: Config{AutoOptions: true}, method: "PATCH", route: "/v1/opt", wantCode: 405, wantAllow: "POST, OPTIONS"},
+		},
+		{
+			name: "head_tree_without_path",
+			args: args{method: "POST", route: "/v1/y", wantCode: 405, wantAllow: "GET, HEAD"},
+		},
+		{
+			name: "head_only",
+			args: args{method: "POST", route: "/v1/x", wantCode: 405, wantAllow: "HEAD"},
+		},
+		{
+			name: "explicit_options_route",
+			args: args{config: Config{AutoOptions: true}, method: "OPTIONS", route: "/v1/opt", wantCode: 200},
+		},
+		{
+			name: "not_found",
+			args: args{method: "GET", route: "/v1/nothing", wantCode: 404},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := newApp(tt.args.config).QuickTest(tt.args.method, tt.args.route, nil)
+			if err != nil {
+				t.Errorf("error: %v", err)
+				return
+			}
+
+			if tt.args.wantCode != data.StatusCode() {
+				t.Errorf("was suppose to return %d and %d come", tt.args.wantCode, data.StatusCode())
+			}
+
+			if got := data.Response().Header.Get("Allow"); got != tt.args.wantAllow {
+				t.Errorf("was suppose to return Allow %q and %q come", tt.args.wantAllow, got)
+			}
+		})
+	}
+}
+
+// cover     -> go test -v -count=1 -cover -failfast -run ^TestQuick_CustomHandlers$
+// coverHTML -> go test -v -count=1 -failfast -cover -coverprofile=coverage.out -run ^TestQuick_CustomHandlers$; go tool cover -html=coverage.out
+func TestQuick_CustomHandlers(t *testing.T) {
+	type args struct {
+		method   string
+		route    string
+		wantCode int
+		wantOut  string
+	}
+
+	r := New(Config{
+		ErrorHandler: func(c *Ctx, err error) {
+			c.Status(http.StatusInternalServerError).JSON(map[string]string{"error": "internal"})
+		},
+	})
+	r.NotFound(func(c *Ctx) error {
+		return c.JSON(map[string]string{"error": "not found", "path": c.Request.URL.Path})
+	})
+	r.MethodNotAllowed(func(c *Ctx) error {
+		return c.JSON(map[string]string{"error": "method not allowed", "allow": c.Response.Header().Get("Allow")})
+	})
+
+	failing := func(c *Ctx) error {
+		return fmt.Errorf("db password is hunter2")
+	}
+	r.Get("/v1/fail", failing)
+	r.Group("/v2").Post("/fail", failing)
+	r.Get("/v1/user", func(c *Ctx) error { return c.SendString("ok") })
+
+	tests := []struct {
+		name string
+		args args
+	}{
+		{name: "route_error", args: args{method: "GET", route: "/v1/fail", wantCode: 500, wantOut: `{"error":"internal"}`}},
+		{name: "group_error", args: args{method: "POST", route: "/v2/fail", wantCode: 500, wantOut: `{"error":"internal"}`}},
+		{name: "not_found", args: args{method: "GET", route: "/nothing", wantCode: 404, wantOut: `{"error":"not found","path":"/nothing"}`}},
+		{name: "method_not_allowed", args: args{method: "DELETE", route: "/v1/user", wantCode: 405, wantOut: `{"allow":"GET, HEAD","error":"method not allowed"}`}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := r.QuickTest(tt.args.method, tt.args.route, nil)
+			if err != nil {
+				t.Errorf("error: %v", err)
+				return
+			}
+
+			if data.BodyStr() != tt.args.wantOut {
+				t.Errorf("was suppose to return %s and %s come", tt.args.wantOut, data.BodyStr())
+			}
+
+			if tt.args.wantCode != data.StatusCode() {
+				t.Errorf("was suppose to return %d and %d come", tt.args.wantCode, data.StatusCode())
+			}
+		})
+	}
+}
+
+// cover     -> go test -v -count=1 -cover -failfast -run ^TestQuick_Body$
+// coverHTML -> go test -v -count=1 -failfast -cover -coverprofile=coverage.out -run ^TestQuick_Body$; go tool cover -html=coverage.out
+func TestQuick_Body(t *testing.T) {
+	type user struct {
+		Name string `json:"name"`
+	}
+
+	r := New(Config{MaxBodySize: 16})
+	r.Post("/bind", func(c *Ctx) error {
+		var u user
+		if err := c.Bind(&u); err != nil {
+			return err
+		}
+		return c.SendString(u.Name)
+	})
+	r.Post("/body", func(c *Ctx) error {
+		return c.SendString(concat.String("len=", fmt.Sprint(len(c.Body()))))
+	})
+	r.Post("/ignore", func(c *Ctx) error {
+		return c.SendString("ok")
+	})
+	r.Post("/stream", func(c *Ctx) error {
+		n, err := io.Copy(io.Discard, c.BodyReader())
+		if err != nil {
+			return err
+		}
+		return c.SendString(fmt.Sprint(n))
+	})
+	r.Post("/upload", func(c *Ctx) error {
+		n, err := io.Copy(io.Discard, c.BodyReader())
+		if err != nil {
+			return err
+		}
+		return c.SendString(concat.String(fmt.Sprint(n), " body=", fmt.Sprint(len(c.Body()))))
+	}, RouteConfig{MaxBodySize: -1, NoBuffer: true})
+	r.Post("/nobuffer", func(c *Ctx) error {
+		var u user
+		if err := c.Bind(&u); err != nil {
+			return err
+		}
+		return c.SendString(concat.String(u.Name, " body=", fmt.Sprint(len(c.Body()))))
+	}, RouteConfig{NoBuffer: true})
+	r.Post("/nobuffer/twice", func(c *Ctx) error {
+		var u user
+		if err := c.Bind(&u); err != nil {
+			return err
+		}
+		err := c.Bind(&u)
+		if !errors.Is(err, ErrInternalServerError) || !strings.Contains(err.Error(), "already read") {
+			t.Errorf("was suppose to return the body already read error and %v come", err)
+		}
+		return err
+	}, RouteConfig{NoBuffer: true})
+
+	big := []byte(`{"name":"` + strings.Repeat("j", 64) + `"}`)
+	small := []byte(`{"name":"jeff"}`)
+	jsonHeader := map[string]string{"Content-Type": "application/json"}
+
+	tests := []struct {
+		name     string
+		route    string
+		body     []byte
+		wantCode int
+		wantOut  string
+	}{
+		// QuickTest sends the body without Content-Length, like a chunked upload
+		{name: "bind", route: "/bind", body: small, wantCode: 200, wantOut: "jeff"},
+		{name: "bind_too_large", route: "/bind", body: big, wantCode: 413, wantOut: "Request Entity Too Large"},
+		{name: "body_too_large", route: "/body", body: big, wantCode: 200, wantOut: "len=0"},
+		{name: "not_read", route: "/ignore", body: big, wantCode: 200, wantOut: "ok"},
+		{name: "stream_too_large", route: "/stream", body: big, wantCode: 413, wantOut: "Request Entity Too Large"},
+		{name: "route_without_limit", route: "/upload", body: big, wantCode: 200, wantOut: "75 body=0"},
+		{name: "bind_no_buffer", route: "/nobuffer", body: small, wantCode: 200, wantOut: "jeff body=0"},
+		{name: "bind_no_buffer_twice", route: "/nobuffer/twice", body: small, wantCode: 500, wantOut: "Internal Server Error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := r.QuickTest("POST", tt.route, jsonHeader, tt.body)
+			if err != nil {
+				t.Fatalf("error: %v", err)
+			}
+			if data.StatusCode() != tt.wantCode || data.BodyStr() != tt.wantOut {
+				t.Errorf("was suppose to return %d %q and %d %q come", tt.wantCode, tt.wantOut, data.StatusCode(), data.BodyStr())
+			}
+		})
+	}
+
+	t.Run("content_length", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest("POST", "/ignore", bytes.NewReader(big))
+		r.ServeHTTP(rec, req)
+		if rec.Code != 413 {
+			t.Errorf("was suppose to return 413 before the handler and %d come", rec.Code)
+		}
+	})
+}
@@ -0,0 +1,206 @@
+package quick
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// readyFdEnv names the descriptor the process started by Restart writes
+// to once it serves, so the parent knows it can stop.
+const readyFdEnv = "QUICK_READY_FD"
+
+var (
+	inheritedOnce sync.Once
+	inheritedMu   sync.Mutex
+	inherited     []net.Listener
+	readyOnce     sync.Once
+)
+
+// listen opens a listener for network and addr, or takes the next one
+// inherited through LISTEN_FDS for the same network. That is how the
+// process started by Restart picks up the parent's sockets, in the order
+// the parent opened them.
+func (q *Quick) listen(network, addr string) (net.Listener, error) {
+	ln := inheritedListener(network)
+	if ln == nil {
+		var err error
+		ln, err = net.Listen(network, addr)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	q.track(ln)
+	return ln, nil
+}
+
+// inheritedListener takes the first inherited listener for network.
+func inheritedListener(network string) net.Listener {
+	inheritedOnce.Do(func() {
+		inherited, _ = ActivationListeners()
+	})
+
+	inheritedMu.Lock()
+	defer inheritedMu.Unlock()
+
+	for i, ln := range inherited {
+		if ln.Addr().Network() == network {
+			inherited = append(inherited[:i], inherited[i+1:]...)
+			return ln
+		}
+	}
+	return nil
+}
+
+// track keeps ln so Restart can hand it over to the new process.
+func (q *Quick) track(ln net.Listener) {
+	listeners, _ := q.listeners.Load().([]net.Listener)
+	q.listeners.Store(append(listeners[:len(listeners):len(listeners)], ln))
+}
+
+// Restart performs a zero-downtime binary upgrade: it starts the current
+// executable again with the same arguments, passes it every listening
+// socket through LISTEN_FDS and waits until the new process serves on
+// them. Only then is this server shut down gracefully, so Listen returns
+// nil once the open requests are drained. When the new process exits or
+// is not serving within Config.RestartTimeout it is killed, this server
+// keeps running and Restart returns an error. With Config.GracefulRestart
+// a SIGHUP calls Restart.
+func (q *Quick) Restart() error {
+	listeners, _ := q.listeners.Load().([]net.Listener)
+	if len(listeners) == 0 {
+		return errors.New("quick: no listener to hand over")
+	}
+
+	files := make([]*os.File, 0, len(listeners)+1)
+	defer func() {
+		for _, f := range files {
+			f.Close()
+		}
+	}()
+
+	for _, ln := range listeners {
+		fl, ok := ln.(interface{ File() (*os.File, error) })
+		if !ok {
+			return errors.New("quick: listener " + ln.Addr().String() + " can not be handed over")
+		}
+		f, err := fl.File()
+		if err != nil {
+			return err
+		}
+		files = append(files, f)
+	}
+
+	path, err := os.Executable()
+	if err != nil {
+		return err
+	}
+
+	// the new process gets the write end after the listeners
+	ready, readyW, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+	defer ready.Close()
+	files = append(files, readyW)
+
+	cmd := exec.Command(path, os.Args[1:]...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.ExtraFiles = files
+	cmd.Env = append(restartEnv(),
+		"LISTEN_FDS="+strconv.Itoa(len(listeners)),
+		readyFdEnv+"="+strconv.Itoa(listenFdsStart+len(listeners)))
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	// only the new process may hold the write end, so its exit ends the read
+	readyW.Close()
+	files = files[:len(files)-1]
+	// Start switched the sockets to blocking mode, which would stall the
+	// Accept and Shutdown of this server if the new process fails
+	for _, f := range files {
+		setNonblock(f)
+	}
+
+	if err := q.waitReady(ready); err != nil {
+		cmd.Process.Kill()
+		cmd.Wait()
+		return err
+	}
+	go cmd.Wait()
+
+	for _, ln := range listeners {
+		// the socket file now belongs to the new process as well
+		if ul, ok := ln.(interface{ SetUnlinkOnClose(bool) }); ok {
+			ul.SetUnlinkOnClose(false)
+		}
+	}
+
+	if stop, _ := q.stop.Load().(context.CancelFunc); stop != nil {
+		stop()
+	}
+	return nil
+}
+
+// waitReady waits for the byte the new process writes once it serves.
+func (q *Quick) waitReady(ready *os.File) error {
+	timeout := q.config.RestartTimeout
+	if timeout <= 0 {
+		timeout = defaultConfig.RestartTimeout
+	}
+	ready.SetReadDeadline(time.Now().Add(timeout))
+
+	b := make([]byte, 1)
+	if _, err := ready.Read(b); err != nil {
+		if errors.Is(err, io.EOF) {
+			return errors.New("quick: new process exited before serving")
+		}
+		if errors.Is(err, os.ErrDeadlineExceeded) {
+			return errors.New("quick: new process not serving after " + timeout.String())
+		}
+		return err
+	}
+	return nil
+}
+
+// notifyReady tells the process that called Restart that this one
+// serves. It runs once, from the first server started.
+func notifyReady() {
+	readyOnce.Do(func() {
+		fd := os.Getenv(readyFdEnv)
+		if len(fd) == 0 {
+			return
+		}
+		os.Unsetenv(readyFdEnv)
+
+		n, err := strconv.Atoi(fd)
+		if err != nil {
+			return
+		}
+		f := os.NewFile(uintptr(n), "ready")
+		f.Write([]byte{1})
+		f.Close()
+	})
+}
+
+// restartEnv is the environment without LISTEN_* and QUICK_READY_FD.
+func restartEnv() []string {
+	env := os.Environ()
+	out := env[:0:0]
+	for _, kv := range env {
+		if !strings.HasPrefix(kv, "LISTEN_") && !strings.HasPrefix(kv, readyFdEnv+"=") {
+			out = append(out, kv)
+		}
+	}
+	return out
+}
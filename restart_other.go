@@ -0,0 +1,7 @@
+//go:build !unix
+
+package quick
+
+import "os"
+
+func setNonblock(f *os.File) {}
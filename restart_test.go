@@ -0,0 +1,143 @@
+package quick
+
+import (
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// go test -v -count=1 -failfast -run ^TestQuick_Restart$
+func TestQuick_Restart(t *testing.T) {
+	if addr := os.Getenv("QUICK_TEST_RESTART_ADDR"); len(addr) > 0 {
+		// the process started by Restart fails before serving
+		if len(os.Getenv("QUICK_TEST_RESTART_FAIL")) > 0 && len(os.Getenv("LISTEN_FDS")) > 0 {
+			os.Exit(3)
+		}
+		q := New(Config{HandleSignals: true, GracefulRestart: true})
+		q.Get("/pid", func(c *Ctx) error {
+			return c.SendString(strconv.Itoa(os.Getpid()))
+		})
+		if err := q.Listen(addr); err != nil {
+			os.Exit(2)
+		}
+		os.Exit(0)
+	}
+
+	if runtime.GOOS == "windows" {
+		t.Skip("descriptor passing")
+	}
+
+	addr := freeAddr(t)
+	cmd, parent := startRestartServer(t, addr)
+	defer cmd.Process.Kill()
+	getPid := func() (int, error) { return restartPid(addr) }
+
+	cmd.Process.Signal(syscall.SIGHUP)
+
+	done := make(chan error, 1)
+	go func() { done <- cmd.Wait() }()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("parent should exit cleanly after handing over, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("parent did not exit after SIGHUP")
+	}
+
+	child, err := getPid()
+	if err != nil {
+		t.Fatalf("the new process should keep serving on %s: %v", addr, err)
+	}
+	if child == parent {
+		t.Errorf("was suppose to be served by a new process and %d come", child)
+	}
+
+	if p, err := os.FindProcess(child); err == nil {
+		p.Signal(syscall.SIGTERM)
+	}
+}
+
+// startRestartServer runs TestQuick_Restart in a subprocess serving on
+// addr and returns it with its pid as reported over HTTP.
+func startRestartServer(t *testing.T, addr string, env ...string) (*exec.Cmd, int) {
+	t.Helper()
+
+	cmd := exec.Command(os.Args[0], "-test.run=^TestQuick_Restart$")
+	cmd.Env = append(append(os.Environ(), "QUICK_TEST_RESTART_ADDR="+addr), env...)
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	pid, err := restartPid(addr)
+	for i := 0; i < 100 && err != nil; i++ {
+		time.Sleep(10 * time.Millisecond)
+		pid, err = restartPid(addr)
+	}
+	if err != nil || pid != cmd.Process.Pid {
+		cmd.Process.Kill()
+		t.Fatalf("was suppose to be served by %d and %d come: %v", cmd.Process.Pid, pid, err)
+	}
+	return cmd, pid
+}
+
+func restartPid(addr string) (int, error) {
+	// a pooled connection would keep the old process busy until it times out
+	client := &http.Client{Transport: &http.Transport{DisableKeepAlives: true}}
+	resp, err := client.Get("http://" + addr + "/pid")
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(string(b))
+}
+
+// go test -v -count=1 -failfast -run ^TestQuick_RestartFailedChild$
+func TestQuick_RestartFailedChild(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("descriptor passing")
+	}
+
+	addr := freeAddr(t)
+	cmd, parent := startRestartServer(t, addr, "QUICK_TEST_RESTART_FAIL=1")
+	defer cmd.Process.Kill()
+
+	done := make(chan error, 1)
+	go func() { done <- cmd.Wait() }()
+
+	for i := 0; i < 3; i++ {
+		cmd.Process.Signal(syscall.SIGHUP)
+		select {
+		case err := <-done:
+			t.Fatalf("parent should keep running when the new process fails, exited with %v", err)
+		case <-time.After(300 * time.Millisecond):
+		}
+
+		pid, err := restartPid(addr)
+		if err != nil || pid != parent {
+			t.Fatalf("was suppose to be served by %d and %d come: %v", parent, pid, err)
+		}
+	}
+
+	cmd.Process.Signal(syscall.SIGTERM)
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Errorf("parent did not exit after SIGTERM")
+	}
+}
+
+func TestQuick_RestartWithoutListener(t *testing.T) {
+	if err := New().Restart(); err == nil {
+		t.Errorf("Restart() without a listener should fail")
+	}
+}
@@ -0,0 +1,14 @@
+//go:build unix
+
+package quick
+
+import (
+	"os"
+	"syscall"
+)
+
+// setNonblock puts the socket behind f back in non-blocking mode. The
+// flag is shared with the listener f was duplicated from.
+func setNonblock(f *os.File) {
+	syscall.SetNonblock(int(f.Fd()), true)
+}
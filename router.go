@@ -0,0 +1,340 @@
+package quick
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/jeffotoni/quick/internal/concat"
+)
+
+// router keeps one radix tree per HTTP method.
+// Lookup precedence on every level is: static > :param > {regex} > *wildcard.
+type router struct {
+	trees map[string]*node
+}
+
+// node is a radix tree node. Static nodes consume their prefix,
+// param and regex nodes consume a whole path segment and wildcard
+// nodes consume the rest of the path.
+//
+// Param names live on the node holding the handler, in path order, so
+// routes sharing a :param or *wildcard node may name it differently,
+// e.g. /user/:id and /user/:uid/posts.
+type node struct {
+	prefix   string
+	indices  string
+	statics  []*node
+	param    *node
+	regexs   []*node
+	wildcard *node
+	name     string
+	segment  string
+	rgx      *regexp.Regexp
+	handler  http.HandlerFunc
+	names    []string
+}
+
+// add registers the handler for method and pattern.
+// When the same method and pattern are registered twice the first one wins,
+// just like it did with the old linear route scan.
+func (r *router) add(method, pattern string, handler http.HandlerFunc) {
+	if r.trees == nil {
+		r.trees = make(map[string]*node)
+	}
+
+	root := r.trees[method]
+	if root == nil {
+		root = new(node)
+		r.trees[method] = root
+	}
+
+	if len(pattern) == 0 {
+		pattern = "/"
+	}
+
+	n := root
+	var names []string
+	for len(pattern) > 0 {
+		start := strings.Index(pattern, "/:")
+		for _, open := range []string{"/{", "/*"} {
+			if i := strings.Index(pattern, open); i >= 0 && (start < 0 || i < start) {
+				start = i
+			}
+		}
+
+		if start < 0 {
+			n = n.addStatic(pattern)
+			break
+		}
+
+		// the static part keeps the slash that opens the segment
+		n = n.addStatic(pattern[:start+1])
+		pattern = pattern[start+1:]
+
+		end := strings.IndexByte(pattern, '/')
+		if end < 0 {
+			end = len(pattern)
+		}
+		segment := pattern[:end]
+		pattern = pattern[end:]
+
+		switch segment[0] {
+		case ':':
+			if len(segment) == 1 {
+				panic("quick: param segment needs a name")
+			}
+			n = n.addParam()
+			names = append(names, segment[1:])
+		case '*':
+			if len(pattern) > 0 {
+				panic("quick: wildcard " + segment + " must be the last segment")
+			}
+			n = n.addWildcard()
+			// the param key is the whole segment, "*name" or just "*"
+			names = append(names, segment)
+		default:
+			n = n.addRegex(segment)
+			names = append(names, n.name)
+		}
+	}
+
+	if n.handler == nil {
+		n.handler = handler
+		n.names = names
+	}
+}
+
+// lookup returns the handler registered for method and path and the
+// params collected on the way. params stays nil when the route has none,
+// so static routes are matched without allocating.
+func (r *router) lookup(method, path string) (http.HandlerFunc, map[string]string) {
+	root := r.trees[method]
+	if root == nil {
+		return nil, nil
+	}
+
+	var values []string
+	n := root.match(path, &values)
+	if n == nil {
+		return nil, nil
+	}
+	if len(n.names) == 0 {
+		return n.handler, nil
+	}
+
+	// values were collected from the end of the path
+	params := make(map[string]string, len(n.names))
+	for i, name := range n.names {
+		params[name] = values[len(values)-1-i]
+	}
+	return n.handler, params
+}
+
+// allowed returns the methods with a route for path, in the order of
+// methods followed by any custom method sorted by name. HEAD is listed
+// whenever GET is, since GET routes answer HEAD requests too.
+func (r *router) allowed(path string) []string {
+	var allow []string
+	var values []string
+
+	for _, method := range methods {
+		if r.matches(method, path, &values) ||
+			(method == http.MethodHead && r.matches(http.MethodGet, path, &values)) {
+			allow = append(allow, method)
+		}
+	}
+
+	var custom []string
+	for method, root := range r.trees {
+		if !isAllowed(methods, method) && root.match(path, &values) != nil {
+			custom = append(custom, method)
+		}
+	}
+	sort.Strings(custom)
+
+	return append(allow, custom...)
+}
+
+func (r *router) matches(method, path string, values *[]string) bool {
+	root := r.trees[method]
+	return root != nil && root.match(path, values) != nil
+}
+
+func (n *node) addStatic(s string) *node {
+	for {
+		i := strings.IndexByte(n.indices, s[0])
+		if i < 0 {
+			child := &node{prefix: s}
+			n.indices += s[:1]
+			n.statics = append(n.statics, child)
+			return child
+		}
+
+		child := n.statics[i]
+		l := commonPrefix(child.prefix, s)
+
+		if l < len(child.prefix) {
+			// split the child on the common prefix
+			mid := &node{
+				prefix:  child.prefix[:l],
+				indices: child.prefix[l : l+1],
+				statics: []*node{child},
+			}
+			child.prefix = child.prefix[l:]
+			n.statics[i] = mid
+			child = mid
+		}
+
+		if l == len(s) {
+			return child
+		}
+
+		n = child
+		s = s[l:]
+	}
+}
+
+func (n *node) addParam() *node {
+	if n.param == nil {
+		n.param = new(node)
+	}
+	return n.param
+}
+
+// addWildcard adds the node matching the rest of the path.
+func (n *node) addWildcard() *node {
+	if n.wildcard == nil {
+		n.wildcard = new(node)
+	}
+	return n.wildcard
+}
+
+func (n *node) addRegex(segment string) *node {
+	for _, child := range n.regexs {
+		if child.segment == segment {
+			return child
+		}
+	}
+
+	name, rgx, err := compileRegexSegment(segment)
+	if err != nil {
+		panic(err)
+	}
+
+	child := &node{
+		segment: segment,
+		name:    name,
+		rgx:     rgx,
+	}
+	n.regexs = append(n.regexs, child)
+	return child
+}
+
+// compileRegexSegment parses a {regex} or {name:regex} segment.
+// Unnamed segments keep the raw segment text as their param key.
+// The expression is anchored so it has to match the whole segment.
+func compileRegexSegment(segment string) (name string, rgx *regexp.Regexp, err error) {
+	if len(segment) < 3 || segment[0] != '{' || segment[len(segment)-1] != '}' {
+		return "", nil, fmt.Errorf("quick: invalid regex segment %q", segment)
+	}
+
+	expr := segment[1 : len(segment)-1]
+	name = segment
+	if i := strings.IndexByte(expr, ':'); i > 0 && isParamName(expr[:i]) {
+		name = expr[:i]
+		expr = expr[i+1:]
+	}
+
+	rgx, err = regexp.Compile(concat.String(`^(?:`, expr, `)$`))
+	if err != nil {
+		return "", nil, fmt.Errorf("quick: invalid regex segment %q: %w", segment, err)
+	}
+	return name, rgx, nil
+}
+
+func isParamName(s string) bool {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c != '_' && (c < '0' || c > '9') && (c < 'a' || c > 'z') && (c < 'A' || c > 'Z') {
+			return false
+		}
+	}
+	return true
+}
+
+// match walks the tree below n with the part of the path n did not consume.
+// The value of every param, regex and wildcard segment is appended to
+// values once a full match is found, from the last segment to the first,
+// which keeps backtracking out of dead branches free.
+func (n *node) match(path string, values *[]string) *node {
+	if len(path) == 0 {
+		if n.handler != nil {
+			return n
+		}
+		return n.matchWildcard(path, values)
+	}
+
+	if i := strings.IndexByte(n.indices, path[0]); i >= 0 {
+		child := n.statics[i]
+		if strings.HasPrefix(path, child.prefix) {
+			if found := child.match(path[len(child.prefix):], values); found != nil {
+				return found
+			}
+		}
+	}
+
+	if n.param == nil && len(n.regexs) == 0 {
+		return n.matchWildcard(path, values)
+	}
+
+	end := strings.IndexByte(path, '/')
+	if end < 0 {
+		end = len(path)
+	}
+	if end == 0 {
+		return n.matchWildcard(path, values)
+	}
+	segment := path[:end]
+
+	if n.param != nil {
+		if found := n.param.match(path[end:], values); found != nil {
+			*values = append(*values, segment)
+			return found
+		}
+	}
+
+	for _, child := range n.regexs {
+		if !child.rgx.MatchString(segment) {
+			continue
+		}
+		if found := child.match(path[end:], values); found != nil {
+			*values = append(*values, segment)
+			return found
+		}
+	}
+
+	return n.matchWildcard(path, values)
+}
+
+func (n *node) matchWildcard(path string, values *[]string) *node {
+	if n.wildcard == nil || n.wildcard.handler == nil {
+		return nil
+	}
+	*values = append(*values, path)
+	return n.wildcard
+}
+
+func commonPrefix(a, b string) int {
+	max := len(a)
+	if len(b) < max {
+		max = len(b)
+	}
+	i := 0
+	for i < max && a[i] == b[i] {
+		i++
+	}
+	return i
+}
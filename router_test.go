@@ -0,0 +1,262 @@
+package quick
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+// cover     -> go test -v -count=1 -cover -failfast -run ^TestRouter_lookup$
+// coverHTML -> go test -v -count=1 -failfast -cover -coverprofile=coverage.out -run ^TestRouter_lookup$; go tool cover -html=coverage.out
+func TestRouter_lookup(t *testing.T) {
+	var r router
+	patterns := []string{
+		"/",
+		"/user",
+		"/user/new",
+		"/user/:id",
+		"/user/:id/posts",
+		"/user/{[0-9]+}/avatar",
+		"/users",
+		"/reg/{[0-9]}",
+		"/files/{[a-z]+}",
+		"/files/{[0-9]+}",
+		"/files/*path",
+		"/static/*",
+	}
+	for _, p := range patterns {
+		p := p
+		r.add(http.MethodGet, p, func(w http.ResponseWriter, req *http.Request) {
+			w.Header().Set("Pattern", p)
+		})
+	}
+
+	tests := []struct {
+		name        string
+		method      string
+		path        string
+		wantPattern string
+		wantParams  map[string]string
+	}{
+		{name: "root", method: http.MethodGet, path: "/", wantPattern: "/"},
+		{name: "static", method: http.MethodGet, path: "/user", wantPattern: "/user"},
+		{name: "static_shared_prefix", method: http.MethodGet, path: "/users", wantPattern: "/users"},
+		{name: "static_beats_param", method: http.MethodGet, path: "/user/new", wantPattern: "/user/new"},
+		{
+			name: "param", method: http.MethodGet, path: "/user/42",
+			wantPattern: "/user/:id", wantParams: map[string]string{"id": "42"},
+		},
+		{
+			name: "param_with_tail", method: http.MethodGet, path: "/user/new/posts",
+			wantPattern: "/user/:id/posts", wantParams: map[string]string{"id": "new"},
+		},
+		{
+			name: "backtrack_param_to_regex", method: http.MethodGet, path: "/user/7/avatar",
+			wantPattern: "/user/{[0-9]+}/avatar", wantParams: map[string]string{"{[0-9]+}": "7"},
+		},
+		{
+			name: "regex", method: http.MethodGet, path: "/reg/1",
+			wantPattern: "/reg/{[0-9]}", wantParams: map[string]string{"{[0-9]}": "1"},
+		},
+		{
+			name: "regex_in_registration_order", method: http.MethodGet, path: "/files/123",
+			wantPattern: "/files/{[0-9]+}", wantParams: map[string]string{"{[0-9]+}": "123"},
+		},
+		{name: "regex_must_match_whole_segment", method: http.MethodGet, path: "/reg/12"},
+		{
+			name: "wildcard_after_regex", method: http.MethodGet, path: "/files/a1/b.txt",
+			wantPattern: "/files/*path", wantParams: map[string]string{"*path": "a1/b.txt"},
+		},
+		{
+			name: "wildcard_empty", method: http.MethodGet, path: "/static/",
+			wantPattern: "/static/*", wantParams: map[string]string{"*": ""},
+		},
+		{name: "wildcard_needs_slash", method: http.MethodGet, path: "/static"},
+		{name: "empty_param", method: http.MethodGet, path: "/user/"},
+		{name: "too_long", method: http.MethodGet, path: "/user/42/posts/1"},
+		{name: "unknown", method: http.MethodGet, path: "/nothing"},
+		{name: "other_method", method: http.MethodPost, path: "/user"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, params := r.lookup(tt.method, tt.path)
+			if len(tt.wantPattern) == 0 {
+				if handler != nil {
+					t.Errorf("lookup(%s) should not match", tt.path)
+				}
+				return
+			}
+
+			if handler == nil {
+				t.Errorf("lookup(%s) did not match, want %s", tt.path, tt.wantPattern)
+				return
+			}
+
+			h := make(http.Header)
+			handler(headerWriter(h), nil)
+			if got := h.Get("Pattern"); got != tt.wantPattern {
+				t.Errorf("lookup(%s) matched %s, want %s", tt.path, got, tt.wantPattern)
+			}
+
+			if !reflect.DeepEqual(params, tt.wantParams) {
+				t.Errorf("lookup(%s) params = %v, want %v", tt.path, params, tt.wantParams)
+			}
+		})
+	}
+}
+
+// cover -> go test -v -count=1 -cover -failfast -run ^TestRouter_paramNamesPerRoute$
+func TestRouter_paramNamesPerRoute(t *testing.T) {
+	var r router
+	for _, p := range []string{"/user/:id", "/user/:uid/posts", "/user/:name", "/files/*path", "/files/:dir/*"} {
+		p := p
+		r.add(http.MethodGet, p, func(w http.ResponseWriter, req *http.Request) {
+			w.Header().Set("Pattern", p)
+		})
+	}
+
+	tests := []struct {
+		path        string
+		wantPattern string
+		wantParams  map[string]string
+	}{
+		{path: "/user/42", wantPattern: "/user/:id", wantParams: map[string]string{"id": "42"}},
+		{path: "/user/42/posts", wantPattern: "/user/:uid/posts", wantParams: map[string]string{"uid": "42"}},
+		{path: "/files/a/b.txt", wantPattern: "/files/:dir/*", wantParams: map[string]string{"dir": "a", "*": "b.txt"}},
+		{path: "/files/a", wantPattern: "/files/*path", wantParams: map[string]string{"*path": "a"}},
+	}
+	for _, tt := range tests {
+		handler, params := r.lookup(http.MethodGet, tt.path)
+		if handler == nil {
+			t.Errorf("lookup(%s) did not match, want %s", tt.path, tt.wantPattern)
+			continue
+		}
+		h := make(http.Header)
+		handler(headerWriter(h), nil)
+		if got := h.Get("Pattern"); got != tt.wantPattern {
+			t.Errorf("lookup(%s) matched %s, want %s", tt.path, got, tt.wantPattern)
+		}
+		if !reflect.DeepEqual(params, tt.wantParams) {
+			t.Errorf("lookup(%s) params = %v, want %v", tt.path, params, tt.wantParams)
+		}
+	}
+}
+
+func TestRouter_addWildcardNotLast(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("add() with a wildcard before the last segment should panic")
+		}
+	}()
+
+	var r router
+	r.add(http.MethodGet, "/static/*/file", func(http.ResponseWriter, *http.Request) {})
+}
+
+func TestRouter_lookupStaticNoAlloc(t *testing.T) {
+	var r router
+	for _, p := range []string{"/v1/user", "/v1/user/:id", "/v1/users/list", "/v2/{[a-z]+}"} {
+		r.add(http.MethodGet, p, func(http.ResponseWriter, *http.Request) {})
+	}
+
+	allocs := testing.AllocsPerRun(100, func() {
+		r.lookup(http.MethodGet, "/v1/users/list")
+	})
+	if allocs != 0 {
+		t.Errorf("static lookup allocated %v times, want 0", allocs)
+	}
+}
+
+func BenchmarkRouter_lookup(b *testing.B) {
+	var r router
+	for _, p := range []string{"/v1/user", "/v1/user/:id", "/v1/user/:id/posts", "/v1/users/list"} {
+		r.add(http.MethodGet, p, func(http.ResponseWriter, *http.Request) {})
+	}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		r.lookup(http.MethodGet, "/v1/user/42/posts")
+	}
+}
+
+type headerWriter http.Header
+
+func (h headerWriter) Header() http.Header         { return http.Header(h) }
+func (h headerWriter) Write(b []byte) (int, error) { return len(b), nil }
+func (h headerWriter) WriteHeader(int)             {}
+
+// go test -v -count=1 -failfast -run ^Test_compileRegexSegment$
+func Test_compileRegexSegment(t *testing.T) {
+	tests := []struct {
+		name      string
+		segment   string
+		wantName  string
+		wantMatch string
+		wantErr   bool
+	}{
+		{name: "unnamed", segment: "{[0-9]+}", wantName: "{[0-9]+}", wantMatch: "123"},
+		{name: "named", segment: "{id:[0-9]+}", wantName: "id", wantMatch: "123"},
+		{name: "non_capturing_group", segment: "{(?:a|b)c}", wantName: "{(?:a|b)c}", wantMatch: "bc"},
+		{name: "quantifier_braces", segment: "{code:[A-Z]{2}}", wantName: "code", wantMatch: "BR"},
+		{name: "invalid_regex", segment: "{id:[0-9}", wantErr: true},
+		{name: "empty", segment: "{}", wantErr: true},
+		{name: "unterminated", segment: "{[0-9]+", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotName, rgx, err := compileRegexSegment(tt.segment)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("compileRegexSegment() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if gotName != tt.wantName {
+				t.Errorf("compileRegexSegment() name = %v, want %v", gotName, tt.wantName)
+			}
+			if !rgx.MatchString(tt.wantMatch) {
+				t.Errorf("compileRegexSegment() regex %v should match %v", rgx, tt.wantMatch)
+			}
+		})
+	}
+}
+
+func TestQuick_GetNamedRegex(t *testing.T) {
+	r := New()
+	r.Get("/v1/user/{id:[0-9]+}", func(c *Ctx) error {
+		return c.SendString(c.Param("id"))
+	})
+	g := r.Group("/v2")
+	g.Get("/user/{id:[0-9]+}", func(c *Ctx) error {
+		return c.SendString(c.Param("id"))
+	})
+
+	for _, uri := range []string{"/v1/user/42", "/v2/user/42"} {
+		data, err := r.QuickTest("GET", uri, nil)
+		if err != nil {
+			t.Errorf("error: %v", err)
+			return
+		}
+		if data.StatusCode() != 200 || data.BodyStr() != "42" {
+			t.Errorf("%s: got %d %q, want 200 \"42\"", uri, data.StatusCode(), data.BodyStr())
+		}
+	}
+
+	data, _ := r.QuickTest("GET", "/v1/user/abc", nil)
+	if data.StatusCode() != 404 {
+		t.Errorf("was suppose to return 404 and %d come", data.StatusCode())
+	}
+}
+
+func TestQuick_GetInvalidRegexPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("registering an invalid regex should panic")
+		}
+	}()
+
+	r := New()
+	r.Get("/v1/user/{id:[0-9}", func(c *Ctx) error { return nil })
+}
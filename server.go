@@ -0,0 +1,162 @@
+package quick
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+
+	p "github.com/jeffotoni/quick/internal/print"
+)
+
+// OnStart registers a hook that runs once the listener is open,
+// right before the server starts accepting connections.
+func (q *Quick) OnStart(fn func()) {
+	q.onStart = append(q.onStart, fn)
+}
+
+// OnShutdown registers a hook that runs after a graceful shutdown has
+// drained the open requests, e.g. to close database pools.
+func (q *Quick) OnShutdown(fn func()) {
+	q.onShutdown = append(q.onShutdown, fn)
+}
+
+// ListenWithContext serves on addr like Listen and shuts the server down
+// gracefully when ctx is done, waiting at most Config.ShutdownTimeout for
+// open requests. It returns nil after a clean shutdown.
+func (q *Quick) ListenWithContext(ctx context.Context, addr string, handler ...http.Handler) error {
+	if len(addr) == 0 {
+		addr = ":http"
+	}
+
+	ln, err := q.listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	return q.serveListener(ctx, ln, handler...)
+}
+
+// Serve serves on a listener opened by the caller, e.g. one handed over
+// through socket activation. Like Listen it returns http.ErrServerClosed
+// after Shutdown.
+func (q *Quick) Serve(ln net.Listener, handler ...http.Handler) error {
+	q.track(ln)
+	return q.serveListener(context.Background(), ln, handler...)
+}
+
+// serveListener is Serve with the graceful shutdown on ctx of
+// ListenWithContext.
+func (q *Quick) serveListener(ctx context.Context, ln net.Listener, handler ...http.Handler) error {
+	addr := ln.Addr().String()
+	server := q.httpServer(addr, handler...)
+	if q.config.H2C {
+		if err := q.enableH2C(server); err != nil {
+			ln.Close()
+			return err
+		}
+	}
+
+	p.Stdout("\033[0;33mRun Server Quick:", addr, "\033[0m\n")
+	return q.serve(ctx, server, func() error {
+		return server.Serve(ln)
+	})
+}
+
+// Shutdown gracefully stops the running server: it stops accepting
+// connections, waits for open requests until ctx is done and then runs
+// the OnShutdown hooks.
+func (q *Quick) Shutdown(ctx context.Context) error {
+	server, _ := q.server.Load().(*http.Server)
+	if server == nil {
+		return nil
+	}
+
+	err := server.Shutdown(ctx)
+	if redirect, _ := q.redirect.Load().(*http.Server); redirect != nil {
+		redirect.Shutdown(ctx)
+	}
+	for _, fn := range q.onShutdown {
+		fn()
+	}
+	return err
+}
+
+// serve runs the server with the start hooks and signal handling shared
+// by every Listen variant.
+func (q *Quick) serve(ctx context.Context, server *http.Server, run func() error) error {
+	q.server.Store(server)
+
+	if q.config.HandleSignals {
+		var stop context.CancelFunc
+		ctx, stop = signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+		defer stop()
+	}
+
+	// Restart cancels ctx once the new process is running
+	ctx, stop := context.WithCancel(ctx)
+	defer stop()
+	q.stop.Store(stop)
+
+	if q.config.GracefulRestart {
+		hup := make(chan os.Signal, 1)
+		signal.Notify(hup, syscall.SIGHUP)
+		defer signal.Stop(hup)
+
+		go func() {
+			for {
+				select {
+				case <-hup:
+					// a failed restart keeps this server up, a later SIGHUP retries
+					if err := q.Restart(); err != nil {
+						q.logf("quick: restart failed: %v", err)
+						continue
+					}
+				case <-ctx.Done():
+				}
+				return
+			}
+		}()
+	}
+
+	for _, fn := range q.onStart {
+		fn()
+	}
+
+	errc := make(chan error, 1)
+	go func() {
+		errc <- run()
+	}()
+	// the listener is open and the hooks ran, a parent in Restart can stop
+	notifyReady()
+
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+	}
+
+	timeout := q.config.ShutdownTimeout
+	if timeout <= 0 {
+		timeout = defaultConfig.ShutdownTimeout
+	}
+	sctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	err := q.Shutdown(sctx)
+	if serr := <-errc; !errors.Is(serr, http.ErrServerClosed) {
+		return serr
+	}
+	return err
+}
+
+func (q *Quick) logf(format string, v ...any) {
+	if q.config.ErrorLog != nil {
+		q.config.ErrorLog.Printf(format, v...)
+		return
+	}
+	log.Printf(format, v...)
+}
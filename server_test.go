@@ -0,0 +1,183 @@
+package quick
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// freeAddr returns a local address nobody is listening on.
+func freeAddr(t *testing.T) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+	return addr
+}
+
+// waitServer polls addr until the server answers.
+func waitServer(t *testing.T, url string) {
+	t.Helper()
+	for i := 0; i < 100; i++ {
+		resp, err := http.Get(url)
+		if err == nil {
+			resp.Body.Close()
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("server at %s did not start", url)
+}
+
+// cover     -> go test -v -count=1 -cover -failfast -run ^TestQuick_ListenWithContext$
+// coverHTML -> go test -v -count=1 -failfast -cover -coverprofile=coverage.out -run ^TestQuick_ListenWithContext$; go tool cover -html=coverage.out
+func TestQuick_ListenWithContext(t *testing.T) {
+	addr := freeAddr(t)
+	started := make(chan struct{})
+	var stopped atomic.Bool
+
+	q := New()
+	q.OnStart(func() { close(started) })
+	q.OnShutdown(func() { stopped.Store(true) })
+	q.Get("/ping", func(c *Ctx) error { return c.SendString("pong") })
+	q.Get("/slow", func(c *Ctx) error {
+		time.Sleep(200 * time.Millisecond)
+		return c.SendString("done")
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errc := make(chan error, 1)
+	go func() { errc <- q.ListenWithContext(ctx, addr) }()
+
+	<-started
+	waitServer(t, "http://"+addr+"/ping")
+
+	type result struct {
+		body string
+		err  error
+	}
+	slow := make(chan result, 1)
+	go func() {
+		resp, err := http.Get("http://" + addr + "/slow")
+		if err != nil {
+			slow <- result{err: err}
+			return
+		}
+		defer resp.Body.Close()
+		b, err := io.ReadAll(resp.Body)
+		slow <- result{body: string(b), err: err}
+	}()
+
+	// let the slow request reach the handler before shutting down
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+
+	if err := <-errc; err != nil {
+		t.Errorf("ListenWithContext() error = %v, want nil", err)
+	}
+
+	res := <-slow
+	if res.err != nil || res.body != "done" {
+		t.Errorf("in-flight request should be drained, got %q %v", res.body, res.err)
+	}
+
+	if !stopped.Load() {
+		t.Errorf("OnShutdown hook did not run")
+	}
+
+	if _, err := http.Get("http://" + addr + "/ping"); err == nil {
+		t.Errorf("server should not accept connections after shutdown")
+	}
+}
+
+func TestQuick_Shutdown(t *testing.T) {
+	addr := freeAddr(t)
+
+	q := New()
+	q.Get("/ping", func(c *Ctx) error { return c.SendString("pong") })
+
+	if err := q.Shutdown(context.Background()); err != nil {
+		t.Errorf("Shutdown() before Listen error = %v, want nil", err)
+	}
+
+	errc := make(chan error, 1)
+	go func() { errc <- q.Listen(addr) }()
+	waitServer(t, "http://"+addr+"/ping")
+
+	if err := q.Shutdown(context.Background()); err != nil {
+		t.Errorf("Shutdown() error = %v", err)
+	}
+
+	if err := <-errc; !errors.Is(err, http.ErrServerClosed) {
+		t.Errorf("Listen() error = %v, want http.ErrServerClosed", err)
+	}
+}
+
+// go test -v -count=1 -failfast -run ^TestQuick_httpServer$
+func TestQuick_httpServer(t *testing.T) {
+	errorLog := log.New(io.Discard, "", 0)
+	var connStates int
+	baseCtx := context.WithValue(context.Background(), "tenant", "quick")
+
+	q := New(Config{
+		MaxHeaderBytes:    4096,
+		ReadTimeout:       1 * time.Second,
+		WriteTimeout:      2 * time.Second,
+		IdleTimeout:       3 * time.Second,
+		ReadHeaderTimeout: 4 * time.Second,
+		ErrorLog:          errorLog,
+		ConnState:         func(net.Conn, http.ConnState) { connStates++ },
+		BaseContext:       func(net.Listener) context.Context { return baseCtx },
+	})
+
+	server := q.httpServer(":8080")
+	if server.Handler != q {
+		t.Errorf("server.Handler should be the Quick instance")
+	}
+	if server.MaxHeaderBytes != 4096 {
+		t.Errorf("MaxHeaderBytes = %d, want 4096", server.MaxHeaderBytes)
+	}
+	if server.ReadTimeout != time.Second || server.WriteTimeout != 2*time.Second ||
+		server.IdleTimeout != 3*time.Second || server.ReadHeaderTimeout != 4*time.Second {
+		t.Errorf("timeouts not applied: %v %v %v %v", server.ReadTimeout, server.WriteTimeout, server.IdleTimeout, server.ReadHeaderTimeout)
+	}
+	if server.ErrorLog != errorLog {
+		t.Errorf("ErrorLog not applied")
+	}
+	if server.ConnState(nil, http.StateNew); connStates != 1 {
+		t.Errorf("ConnState not applied")
+	}
+	if server.BaseContext(nil) != baseCtx {
+		t.Errorf("BaseContext not applied")
+	}
+}
+
+func TestQuick_DisableKeepAlives(t *testing.T) {
+	addr := freeAddr(t)
+
+	q := New(Config{DisableKeepAlives: true})
+	q.Get("/ping", func(c *Ctx) error { return c.SendString("pong") })
+
+	go q.Listen(addr)
+	defer q.Shutdown(context.Background())
+	waitServer(t, "http://"+addr+"/ping")
+
+	resp, err := http.Get("http://" + addr + "/ping")
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	resp.Body.Close()
+
+	if !resp.Close {
+		t.Errorf("was suppose to return Connection: close")
+	}
+}
@@ -0,0 +1,354 @@
+package quick
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"html"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jeffotoni/quick/internal/concat"
+)
+
+// StaticConfig tunes Static.
+type StaticConfig struct {
+	// FS serves the files from an fs.FS, e.g. an embed.FS, instead of the
+	// disk. root is then the directory inside FS, "" or "." for its top.
+	FS fs.FS
+	// Index is the file served for a directory.
+	// Default: index.html
+	Index string
+	// Browse lists the directories without an index file.
+	Browse bool
+	// MaxAge sets "Cache-Control: public, max-age=..." on every file.
+	MaxAge time.Duration
+	// CacheControl is sent as it is and takes precedence over MaxAge.
+	CacheControl string
+	// SPA answers the paths without a file with the top Index, so the
+	// client side router of a single page application can handle them.
+	SPA bool
+	// SPAExclude lists the path prefixes, e.g. "/api", that keep their
+	// 404 instead of falling back to the SPA index.
+	SPAExclude []string
+	// Precompressed serves name.br or name.gz in place of name when the
+	// client accepts that encoding and the file exists.
+	Precompressed bool
+}
+
+// Static serves the files under root on disk, or in StaticConfig.FS, at
+// prefix, e.g. Static("/assets", "./public") answers /assets/css/app.css
+// with ./public/css/app.css. Range requests and conditional GETs with
+// ETag and Last-Modified are supported. Paths leaving root are refused.
+func (q *Quick) Static(prefix, root string, opts ...StaticConfig) {
+	var config StaticConfig
+	if len(opts) > 0 {
+		config = opts[0]
+	}
+	if len(config.Index) == 0 {
+		config.Index = "index.html"
+	}
+
+	fsys, err := staticFS(root, config.FS)
+	if err != nil {
+		panic(err)
+	}
+
+	handler := staticHandler(fsys, config)
+	prefix = strings.TrimSuffix(prefix, "/")
+	if len(prefix) > 0 {
+		q.Get(prefix, handler)
+	}
+	q.Get(concat.String(prefix, "/*"), handler)
+}
+
+func staticFS(root string, fsys fs.FS) (fs.FS, error) {
+	if fsys == nil {
+		if len(root) == 0 {
+			root = "."
+		}
+		return os.DirFS(root), nil
+	}
+
+	root = strings.Trim(path.Clean("/"+root), "/")
+	if len(root) == 0 {
+		return fsys, nil
+	}
+	return fs.Sub(fsys, root)
+}
+
+func staticHandler(fsys fs.FS, config StaticConfig) HandleFunc {
+	// ETags of the files without a mod time, which never change
+	etags := new(sync.Map)
+	return func(c *Ctx) error {
+		// cleaning an absolute path drops every ".." that would leave root
+		name := strings.TrimPrefix(path.Clean("/"+c.Param("*")), "/")
+		if len(name) == 0 {
+			name = "."
+		}
+
+		f, info, err := openStatic(fsys, name)
+		if err != nil {
+			if config.SPA && errors.Is(err, ErrNotFound) && !excludeSPA(c.Request.URL.Path, config.SPAExclude) {
+				return serveSPAIndex(c, fsys, etags, config)
+			}
+			return err
+		}
+		defer f.Close()
+
+		if info.IsDir() {
+			urlPath := c.Request.URL.Path
+			if !strings.HasSuffix(urlPath, "/") {
+				// relative links in the page need the trailing slash
+				http.Redirect(c.Response, c.Request, concat.String(path.Base(urlPath), "/"), http.StatusMovedPermanently)
+				return nil
+			}
+
+			indexName := path.Join(name, config.Index)
+			index, indexInfo, err := openStatic(fsys, indexName)
+			if err == nil && !indexInfo.IsDir() {
+				defer index.Close()
+				return serveStatic(c, fsys, etags, indexName, index, indexInfo, config)
+			}
+			switch {
+			case config.Browse:
+				return browseStatic(c, fsys, name, urlPath)
+			case config.SPA && !excludeSPA(urlPath, config.SPAExclude):
+				return serveSPAIndex(c, fsys, etags, config)
+			}
+			return ErrNotFound
+		}
+
+		return serveStatic(c, fsys, etags, name, f, info, config)
+	}
+}
+
+func serveSPAIndex(c *Ctx, fsys fs.FS, etags *sync.Map, config StaticConfig) error {
+	f, info, err := openStatic(fsys, config.Index)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return serveStatic(c, fsys, etags, config.Index, f, info, config)
+}
+
+// excludeSPA reports whether urlPath is one of the prefixes or below one.
+func excludeSPA(urlPath string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		prefix = strings.TrimSuffix(prefix, "/")
+		if strings.HasPrefix(urlPath, prefix) && (len(urlPath) == len(prefix) || urlPath[len(prefix)] == '/') {
+			return true
+		}
+	}
+	return false
+}
+
+func openStatic(fsys fs.FS, name string) (fs.File, fs.FileInfo, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return nil, nil, staticError(err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, nil, staticError(err)
+	}
+	return f, info, nil
+}
+
+func staticError(err error) error {
+	switch {
+	case errors.Is(err, fs.ErrNotExist), errors.Is(err, fs.ErrInvalid):
+		return ErrNotFound.Wrap(err)
+	case errors.Is(err, fs.ErrPermission):
+		return ErrForbidden.Wrap(err)
+	}
+	return err
+}
+
+// precompressed are the encodings served from sibling files, best first.
+var precompressed = []struct{ encoding, ext string }{
+	{"br", ".br"},
+	{"gzip", ".gz"},
+}
+
+// serveStatic answers with the file through http.ServeContent, which takes
+// care of Range, If-Range and the conditional headers.
+func serveStatic(c *Ctx, fsys fs.FS, etags *sync.Map, name string, f fs.File, info fs.FileInfo, config StaticConfig) error {
+	header := c.Response.Header()
+	// ServeContent picks the Content-Type by the name of the original file
+	typeName := info.Name()
+
+	if config.Precompressed {
+		header.Add("Vary", "Accept-Encoding")
+		acceptEncoding := c.Request.Header.Get("Accept-Encoding")
+		for _, p := range precompressed {
+			if len(NegotiateEncoding(acceptEncoding, p.encoding)) == 0 {
+				continue
+			}
+			cname := concat.String(name, p.ext)
+			cf, cinfo, err := openStatic(fsys, cname)
+			if err != nil || cinfo.IsDir() {
+				if cf != nil {
+					cf.Close()
+				}
+				continue
+			}
+			defer cf.Close()
+			header.Set("Content-Encoding", p.encoding)
+			name, f, info = cname, cf, cinfo
+			break
+		}
+	}
+
+	content, ok := f.(io.ReadSeeker)
+	if !ok {
+		b, err := io.ReadAll(f)
+		if err != nil {
+			return err
+		}
+		content = bytes.NewReader(b)
+	}
+
+	etag, err := staticETag(etags, name, content, info)
+	if err != nil {
+		return err
+	}
+
+	header.Set("ETag", etag)
+	switch {
+	case len(config.CacheControl) > 0:
+		header.Set("Cache-Control", config.CacheControl)
+	case config.MaxAge > 0:
+		header.Set("Cache-Control", concat.String("public, max-age=", strconv.FormatInt(int64(config.MaxAge/time.Second), 10)))
+	}
+
+	http.ServeContent(c.Response, c.Request, typeName, info.ModTime(), content)
+	return nil
+}
+
+// SendFile streams the file at path without loading it in memory.
+// Content-Type follows the extension and Range, If-Range and the
+// conditional headers are answered like in Static. A status set with
+// Status other than 200 is sent as it is with the whole file.
+func (c *Ctx) SendFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return staticError(err)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return staticError(err)
+	}
+	if info.IsDir() {
+		return ErrNotFound
+	}
+
+	if c.resStatus != 0 && c.resStatus != http.StatusOK {
+		header := c.Response.Header()
+		if len(header.Get("Content-Type")) == 0 {
+			ctype := mime.TypeByExtension(filepath.Ext(path))
+			if len(ctype) == 0 {
+				ctype = "application/octet-stream"
+			}
+			header.Set("Content-Type", ctype)
+		}
+		header.Set("Content-Length", strconv.FormatInt(info.Size(), 10))
+		c.Response.WriteHeader(c.resStatus)
+		_, err = io.Copy(c.Response, f)
+		return err
+	}
+
+	etag, err := staticETag(nil, "", f, info)
+	if err != nil {
+		return err
+	}
+	c.Set("ETag", etag)
+	http.ServeContent(c.Response, c.Request, info.Name(), info.ModTime(), f)
+	return nil
+}
+
+// Download sends the file at path like SendFile, as an attachment saved
+// under filename, or under the base name of path when filename is empty.
+func (c *Ctx) Download(path, filename string) error {
+	if len(filename) == 0 {
+		filename = filepath.Base(path)
+	}
+	c.Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+	if err := c.SendFile(path); err != nil {
+		// the error page is not a download
+		c.Response.Header().Del("Content-Disposition")
+		return err
+	}
+	return nil
+}
+
+// staticETag builds a weak ETag from size and mod time. Files without a
+// mod time, like the ones in an embed.FS, are hashed instead, once per
+// name when etags is not nil: such a file system can't change.
+func staticETag(etags *sync.Map, name string, content io.ReadSeeker, info fs.FileInfo) (string, error) {
+	if !info.ModTime().IsZero() {
+		return fmt.Sprintf(`W/"%x-%x"`, info.Size(), info.ModTime().UnixNano()), nil
+	}
+	if etags != nil {
+		if etag, ok := etags.Load(name); ok {
+			return etag.(string), nil
+		}
+	}
+
+	h := fnv.New64a()
+	if _, err := io.Copy(h, content); err != nil {
+		return "", err
+	}
+	if _, err := content.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+	etag := fmt.Sprintf(`W/"%x-%x"`, info.Size(), h.Sum64())
+	if etags != nil {
+		etags.Store(name, etag)
+	}
+	return etag, nil
+}
+
+func browseStatic(c *Ctx, fsys fs.FS, name, urlPath string) error {
+	entries, err := fs.ReadDir(fsys, name)
+	if err != nil {
+		return staticError(err)
+	}
+
+	var b strings.Builder
+	title := html.EscapeString(urlPath)
+	b.WriteString("<!doctype html>\n<meta charset=\"utf-8\">\n<title>")
+	b.WriteString(title)
+	b.WriteString("</title>\n<h1>")
+	b.WriteString(title)
+	b.WriteString("</h1>\n<pre>\n")
+	if name != "." {
+		b.WriteString("<a href=\"../\">../</a>\n")
+	}
+	for _, e := range entries {
+		entry := e.Name()
+		if e.IsDir() {
+			entry += "/"
+		}
+		link := url.URL{Path: entry}
+		fmt.Fprintf(&b, "<a href=\"%s\">%s</a>\n", html.EscapeString(link.String()), html.EscapeString(entry))
+	}
+	b.WriteString("</pre>\n")
+
+	c.Set("Content-Type", "text/html; charset=utf-8")
+	return c.SendString(b.String())
+}
@@ -0,0 +1,287 @@
+package quick
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+// cover     -> go test -v -count=1 -cover -failfast -run ^TestQuick_Static$
+// coverHTML -> go test -v -count=1 -failfast -cover -coverprofile=coverage.out -run ^TestQuick_Static$; go tool cover -html=coverage.out
+func TestQuick_Static(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "css"), 0755)
+	os.MkdirAll(filepath.Join(dir, "docs"), 0755)
+	os.WriteFile(filepath.Join(dir, "index.html"), []byte("<h1>home</h1>"), 0644)
+	os.WriteFile(filepath.Join(dir, "css", "app.css"), []byte("body{color:red}"), 0644)
+	os.WriteFile(filepath.Join(dir, "docs", "a.txt"), []byte("0123456789"), 0644)
+	os.WriteFile(filepath.Join(filepath.Dir(dir), "secret.txt"), []byte("secret"), 0644)
+
+	q := New()
+	q.Static("/assets", dir, StaticConfig{Browse: true, MaxAge: time.Hour})
+
+	tests := []struct {
+		name        string
+		uri         string
+		headers     map[string]string
+		wantStatus  int
+		wantBody    string
+		wantHeaders map[string]string
+	}{
+		{
+			name: "file", uri: "/assets/css/app.css", wantStatus: 200, wantBody: "body{color:red}",
+			wantHeaders: map[string]string{"Content-Type": "text/css; charset=utf-8", "Cache-Control": "public, max-age=3600"},
+		},
+		{name: "index", uri: "/assets/", wantStatus: 200, wantBody: "<h1>home</h1>"},
+		{
+			name: "dir_redirect", uri: "/assets/docs", wantStatus: 301,
+			wantHeaders: map[string]string{"Location": "/assets/docs/"},
+		},
+		{name: "prefix_redirect", uri: "/assets", wantStatus: 301, wantHeaders: map[string]string{"Location": "/assets/"}},
+		{name: "browse", uri: "/assets/docs/", wantStatus: 200, wantBody: `<a href="a.txt">a.txt</a>`},
+		{
+			name: "range", uri: "/assets/docs/a.txt", headers: map[string]string{"Range": "bytes=2-4"},
+			wantStatus: 206, wantBody: "234", wantHeaders: map[string]string{"Content-Range": "bytes 2-4/10"},
+		},
+		{name: "missing", uri: "/assets/nothing.js", wantStatus: 404},
+		{name: "traversal", uri: "/assets/../secret.txt", wantStatus: 404},
+		{name: "encoded_traversal", uri: "/assets/%2e%2e/secret.txt", wantStatus: 404},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := q.QuickTest("GET", tt.uri, tt.headers)
+			if err != nil {
+				t.Fatalf("error: %v", err)
+			}
+			if data.StatusCode() != tt.wantStatus {
+				t.Errorf("was suppose to return %d and %d come", tt.wantStatus, data.StatusCode())
+			}
+			if !strings.Contains(data.BodyStr(), tt.wantBody) {
+				t.Errorf("was suppose to return %q and %q come", tt.wantBody, data.BodyStr())
+			}
+			for k, v := range tt.wantHeaders {
+				if got := data.Response().Header.Get(k); got != v {
+					t.Errorf("header %s: was suppose to be %q and %q come", k, v, got)
+				}
+			}
+		})
+	}
+
+	t.Run("conditional_get", func(t *testing.T) {
+		data, _ := q.QuickTest("GET", "/assets/css/app.css", nil)
+		etag := data.Response().Header.Get("ETag")
+		lastModified := data.Response().Header.Get("Last-Modified")
+		if len(etag) == 0 || len(lastModified) == 0 {
+			t.Fatalf("was suppose to set ETag and Last-Modified and %q %q come", etag, lastModified)
+		}
+
+		data, _ = q.QuickTest("GET", "/assets/css/app.css", map[string]string{"If-None-Match": etag})
+		if data.StatusCode() != 304 {
+			t.Errorf("If-None-Match: was suppose to return 304 and %d come", data.StatusCode())
+		}
+		data, _ = q.QuickTest("GET", "/assets/css/app.css", map[string]string{"If-Modified-Since": lastModified})
+		if data.StatusCode() != 304 {
+			t.Errorf("If-Modified-Since: was suppose to return 304 and %d come", data.StatusCode())
+		}
+	})
+}
+
+func TestQuick_StaticFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"dist/index.html":  {Data: []byte("<h1>spa</h1>")},
+		"dist/app.js":      {Data: []byte("console.log(1)")},
+		"dist/sub/data.js": {Data: []byte("{}")},
+	}
+
+	q := New()
+	q.Static("/", "dist", StaticConfig{FS: fsys, CacheControl: "no-cache"})
+
+	data, _ := q.QuickTest("GET", "/app.js", nil)
+	if data.StatusCode() != 200 || data.BodyStr() != "console.log(1)" {
+		t.Errorf("was suppose to return 200 console.log(1) and %d %s come", data.StatusCode(), data.BodyStr())
+	}
+	if cc := data.Response().Header.Get("Cache-Control"); cc != "no-cache" {
+		t.Errorf("was suppose to set Cache-Control no-cache and %q come", cc)
+	}
+
+	etag := data.Response().Header.Get("ETag")
+	data, _ = q.QuickTest("GET", "/app.js", map[string]string{"If-None-Match": etag})
+	if len(etag) == 0 || data.StatusCode() != 304 {
+		t.Errorf("was suppose to return 304 for ETag %q and %d come", etag, data.StatusCode())
+	}
+
+	// the hash is computed once, an embed.FS never changes
+	fsys["dist/app.js"].Data = []byte("console.log(2)")
+	data, _ = q.QuickTest("GET", "/app.js", nil)
+	if got := data.Response().Header.Get("ETag"); got != etag {
+		t.Errorf("was suppose to reuse ETag %q and %q come", etag, got)
+	}
+
+	data, _ = q.QuickTest("GET", "/", nil)
+	if data.BodyStr() != "<h1>spa</h1>" {
+		t.Errorf("was suppose to serve the index and %q come", data.BodyStr())
+	}
+
+	data, _ = q.QuickTest("GET", "/sub/", nil)
+	if data.StatusCode() != 404 {
+		t.Errorf("directory without index and Browse: was suppose to return 404 and %d come", data.StatusCode())
+	}
+
+	data, _ = q.QuickTest("HEAD", "/app.js", nil)
+	if data.StatusCode() != 200 || len(data.Body()) != 0 {
+		t.Errorf("HEAD: was suppose to return 200 without body and %d %q come", data.StatusCode(), data.BodyStr())
+	}
+}
+
+// go test -v -count=1 -failfast -run ^TestQuick_StaticSPA$
+func TestQuick_StaticSPA(t *testing.T) {
+	fsys := fstest.MapFS{
+		"build/index.html":      {Data: []byte("<div id=root></div>")},
+		"build/app.js":          {Data: []byte("plain")},
+		"build/app.js.br":       {Data: []byte("brotli")},
+		"build/app.js.gz":       {Data: []byte("gzip")},
+		"build/style.css":       {Data: []byte("css")},
+		"build/assets/logo.svg": {Data: []byte("logo")},
+	}
+
+	q := New()
+	q.Get("/api/user", func(c *Ctx) error {
+		return c.SendString("user")
+	})
+	q.Static("/", "build", StaticConfig{FS: fsys, SPA: true, SPAExclude: []string{"/api"}, Precompressed: true})
+
+	tests := []struct {
+		name           string
+		uri            string
+		acceptEncoding string
+		wantStatus     int
+		wantBody       string
+		wantEncoding   string
+	}{
+		{name: "client_route", uri: "/users/42/edit", wantStatus: 200, wantBody: "<div id=root></div>"},
+		{name: "dir_without_index", uri: "/assets/", wantStatus: 200, wantBody: "<div id=root></div>"},
+		{name: "api_route", uri: "/api/user", wantStatus: 200, wantBody: "user"},
+		{name: "api_excluded", uri: "/api/nothing", wantStatus: 404},
+		{name: "api_prefix_only_on_segments", uri: "/apiary", wantStatus: 200, wantBody: "<div id=root></div>"},
+		{name: "br", uri: "/app.js", acceptEncoding: "gzip, br", wantStatus: 200, wantBody: "brotli", wantEncoding: "br"},
+		{name: "gzip", uri: "/app.js", acceptEncoding: "gzip", wantStatus: 200, wantBody: "gzip", wantEncoding: "gzip"},
+		{name: "br_refused", uri: "/app.js", acceptEncoding: "*, br;q=0", wantStatus: 200, wantBody: "gzip", wantEncoding: "gzip"},
+		{name: "identity", uri: "/app.js", wantStatus: 200, wantBody: "plain"},
+		{name: "no_sibling", uri: "/style.css", acceptEncoding: "br", wantStatus: 200, wantBody: "css"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			headers := map[string]string{}
+			if len(tt.acceptEncoding) > 0 {
+				headers["Accept-Encoding"] = tt.acceptEncoding
+			}
+			data, err := q.QuickTest("GET", tt.uri, headers)
+			if err != nil {
+				t.Fatalf("error: %v", err)
+			}
+			if data.StatusCode() != tt.wantStatus {
+				t.Errorf("was suppose to return %d and %d come", tt.wantStatus, data.StatusCode())
+			}
+			if len(tt.wantBody) > 0 && data.BodyStr() != tt.wantBody {
+				t.Errorf("was suppose to return %q and %q come", tt.wantBody, data.BodyStr())
+			}
+			if got := data.Response().Header.Get("Content-Encoding"); got != tt.wantEncoding {
+				t.Errorf("was suppose to be encoded %q and %q come", tt.wantEncoding, got)
+			}
+		})
+	}
+
+	data, _ := q.QuickTest("GET", "/app.js", map[string]string{"Accept-Encoding": "br"})
+	if ct := data.Response().Header.Get("Content-Type"); ct != "text/javascript; charset=utf-8" {
+		t.Errorf("was suppose to keep the type of app.js and %q come", ct)
+	}
+	if vary := data.Response().Header.Get("Vary"); vary != "Accept-Encoding" {
+		t.Errorf("was suppose to set Vary: Accept-Encoding and %q come", vary)
+	}
+}
+
+// go test -v -count=1 -failfast -run ^TestCtx_SendFile$
+func TestCtx_SendFile(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "report.json")
+	os.WriteFile(file, []byte(`{"rows":[1,2,3]}`), 0644)
+	os.WriteFile(filepath.Join(dir, "404.html"), []byte("<h1>nothing here</h1>"), 0644)
+
+	q := New()
+	q.Get("/report", func(c *Ctx) error {
+		return c.SendFile(file)
+	})
+	q.Get("/missing", func(c *Ctx) error {
+		return c.SendFile(filepath.Join(dir, "missing.json"))
+	})
+	q.Get("/notfound", func(c *Ctx) error {
+		return c.Status(404).SendFile(filepath.Join(dir, "404.html"))
+	})
+	q.Get("/download", func(c *Ctx) error {
+		return c.Download(file, "relatório.json")
+	})
+	q.Get("/download/missing", func(c *Ctx) error {
+		return c.Download(filepath.Join(dir, "missing.json"), "")
+	})
+
+	tests := []struct {
+		name        string
+		uri         string
+		headers     map[string]string
+		wantStatus  int
+		wantBody    string
+		wantHeaders map[string]string
+	}{
+		{
+			name: "file", uri: "/report", wantStatus: 200, wantBody: `{"rows":[1,2,3]}`,
+			wantHeaders: map[string]string{"Content-Type": "application/json", "Content-Length": "16"},
+		},
+		{
+			name: "range", uri: "/report", headers: map[string]string{"Range": "bytes=0-6"},
+			wantStatus: 206, wantBody: `{"rows"`, wantHeaders: map[string]string{"Content-Range": "bytes 0-6/16"},
+		},
+		{
+			name: "if_range_mismatch", uri: "/report",
+			headers:    map[string]string{"Range": "bytes=0-6", "If-Range": "Mon, 02 Jan 2006 15:04:05 GMT"},
+			wantStatus: 200, wantBody: `{"rows":[1,2,3]}`,
+		},
+		{name: "missing", uri: "/missing", wantStatus: 404},
+		{
+			name: "status", uri: "/notfound", wantStatus: 404, wantBody: "<h1>nothing here</h1>",
+			wantHeaders: map[string]string{"Content-Type": "text/html; charset=utf-8"},
+		},
+		{
+			name: "download", uri: "/download", wantStatus: 200, wantBody: `{"rows":[1,2,3]}`,
+			wantHeaders: map[string]string{"Content-Disposition": "attachment; filename*=utf-8''relat%C3%B3rio.json"},
+		},
+		{
+			name: "download_missing", uri: "/download/missing", wantStatus: 404,
+			wantHeaders: map[string]string{"Content-Disposition": ""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := q.QuickTest("GET", tt.uri, tt.headers)
+			if err != nil {
+				t.Fatalf("error: %v", err)
+			}
+			if data.StatusCode() != tt.wantStatus {
+				t.Errorf("was suppose to return %d and %d come", tt.wantStatus, data.StatusCode())
+			}
+			if len(tt.wantBody) > 0 && data.BodyStr() != tt.wantBody {
+				t.Errorf("was suppose to return %q and %q come", tt.wantBody, data.BodyStr())
+			}
+			for k, v := range tt.wantHeaders {
+				if got := data.Response().Header.Get(k); got != v {
+					t.Errorf("header %s: was suppose to be %q and %q come", k, v, got)
+				}
+			}
+		})
+	}
+}
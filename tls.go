@@ -0,0 +1,191 @@
+package quick
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/jeffotoni/quick/internal/concat"
+	p "github.com/jeffotoni/quick/internal/print"
+)
+
+// certCheckInterval is how often the certificate files are checked for
+// changes, at most once per interval and only during TLS handshakes.
+var certCheckInterval = time.Second
+
+// ListenTLS serves HTTPS and HTTP/2 on addr. The certificate is loaded from
+// certFile and keyFile and reloaded whenever one of them changes on disk,
+// so renewed certificates are picked up without a restart. Both may be
+// empty when Config.TLSConfig already provides the certificates.
+// With Config.RedirectHTTPAddr a plain HTTP listener redirects to HTTPS.
+func (q *Quick) ListenTLS(addr, certFile, keyFile string, handler ...http.Handler) error {
+	if len(addr) == 0 {
+		addr = ":https"
+	}
+
+	tlsConfig, err := q.tlsConfig(certFile, keyFile)
+	if err != nil {
+		return err
+	}
+
+	ln, err := q.listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	if len(q.config.RedirectHTTPAddr) > 0 {
+		if err := q.listenRedirect(addr); err != nil {
+			ln.Close()
+			return err
+		}
+	}
+
+	server := q.httpServer(addr, handler...)
+	server.TLSConfig = tlsConfig
+	p.Stdout("\033[0;33mRun Server Quick TLS:", addr, "\033[0m\n")
+	return q.serve(context.Background(), server, func() error {
+		return server.ServeTLS(ln, "", "")
+	})
+}
+
+func (q *Quick) tlsConfig(certFile, keyFile string) (*tls.Config, error) {
+	var config *tls.Config
+	if q.config.TLSConfig != nil {
+		config = q.config.TLSConfig.Clone()
+	} else {
+		config = &tls.Config{}
+	}
+
+	if config.MinVersion == 0 {
+		config.MinVersion = tls.VersionTLS12
+	}
+
+	if len(certFile) > 0 || len(keyFile) > 0 {
+		reloader, err := newCertReloader(certFile, keyFile)
+		if err != nil {
+			return nil, err
+		}
+		config.GetCertificate = reloader.GetCertificate
+	}
+	return config, nil
+}
+
+// listenRedirect starts the HTTP server that sends every request to the
+// HTTPS server listening on tlsAddr.
+func (q *Quick) listenRedirect(tlsAddr string) error {
+	ln, err := q.listen("tcp", q.config.RedirectHTTPAddr)
+	if err != nil {
+		return err
+	}
+
+	_, port, _ := net.SplitHostPort(tlsAddr)
+	server := &http.Server{
+		Handler:           redirectHTTPS(port),
+		ReadHeaderTimeout: q.config.ReadHeaderTimeout,
+		ErrorLog:          q.config.ErrorLog,
+	}
+	q.redirect.Store(server)
+
+	go server.Serve(ln)
+	return nil
+}
+
+func redirectHTTPS(port string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		host := req.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		if len(port) > 0 && port != "443" && port != "https" {
+			host = net.JoinHostPort(host, port)
+		}
+		http.Redirect(w, req, concat.String("https://", host, req.URL.RequestURI()), http.StatusPermanentRedirect)
+	})
+}
+
+// certReloader keeps the last good certificate and reloads it when the
+// files change. A failed reload keeps serving the previous certificate.
+type certReloader struct {
+	certFile string
+	keyFile  string
+
+	mu        sync.RWMutex
+	cert      *tls.Certificate
+	certStat  fileStamp
+	keyStat   fileStamp
+	checkedAt time.Time
+}
+
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+func newCertReloader(certFile, keyFile string) (*certReloader, error) {
+	r := &certReloader{certFile: certFile, keyFile: keyFile}
+	if err := r.load(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *certReloader) load() error {
+	certStat, err := stampFile(r.certFile)
+	if err != nil {
+		return err
+	}
+	keyStat, err := stampFile(r.keyFile)
+	if err != nil {
+		return err
+	}
+
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return err
+	}
+
+	r.cert = &cert
+	r.certStat = certStat
+	r.keyStat = keyStat
+	r.checkedAt = time.Now()
+	return nil
+}
+
+func (r *certReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.mu.RLock()
+	cert := r.cert
+	fresh := time.Since(r.checkedAt) < certCheckInterval
+	r.mu.RUnlock()
+
+	if fresh {
+		return cert, nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if time.Since(r.checkedAt) < certCheckInterval {
+		return r.cert, nil
+	}
+	r.checkedAt = time.Now()
+
+	certStat, err1 := stampFile(r.certFile)
+	keyStat, err2 := stampFile(r.keyFile)
+	if err1 == nil && err2 == nil && (certStat != r.certStat || keyStat != r.keyStat) {
+		// keep the old certificate if the new pair is not complete yet
+		r.load()
+	}
+	return r.cert, nil
+}
+
+func stampFile(name string) (fileStamp, error) {
+	fi, err := os.Stat(name)
+	if err != nil {
+		return fileStamp{}, err
+	}
+	return fileStamp{modTime: fi.ModTime(), size: fi.Size()}, nil
+}
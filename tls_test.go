@@ -0,0 +1,162 @@
+package quick
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeSelfSignedCert writes a self-signed certificate for 127.0.0.1
+// with the given common name to certFile and keyFile.
+func writeSelfSignedCert(t *testing.T, certFile, keyFile, commonName string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+
+	serial, _ := rand.Int(rand.Reader, big.NewInt(1<<62))
+	template := x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+
+	certPem := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPem := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer})
+	if err := os.WriteFile(keyFile, keyPem, 0600); err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	if err := os.WriteFile(certFile, certPem, 0600); err != nil {
+		t.Fatalf("error: %v", err)
+	}
+}
+
+func tlsClient() *http.Client {
+	return &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig:   &tls.Config{InsecureSkipVerify: true},
+			ForceAttemptHTTP2: true,
+			DisableKeepAlives: true,
+		},
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// cover     -> go test -v -count=1 -cover -failfast -run ^TestQuick_ListenTLS$
+// coverHTML -> go test -v -count=1 -failfast -cover -coverprofile=coverage.out -run ^TestQuick_ListenTLS$; go tool cover -html=coverage.out
+func TestQuick_ListenTLS(t *testing.T) {
+	defer func(d time.Duration) { certCheckInterval = d }(certCheckInterval)
+	certCheckInterval = 0
+
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+	writeSelfSignedCert(t, certFile, keyFile, "first")
+
+	addr := freeAddr(t)
+	redirectAddr := freeAddr(t)
+
+	q := New(Config{RedirectHTTPAddr: redirectAddr})
+	q.Get("/v1/user", func(c *Ctx) error {
+		return c.SendString(c.Request.Proto)
+	})
+
+	errc := make(chan error, 1)
+	go func() { errc <- q.ListenTLS(addr, certFile, keyFile) }()
+	defer q.Shutdown(context.Background())
+
+	client := tlsClient()
+	var resp *http.Response
+	var err error
+	for i := 0; i < 100; i++ {
+		resp, err = client.Get("https://" + addr + "/v1/user")
+		if err == nil {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+
+	b, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if string(b) != "HTTP/2.0" {
+		t.Errorf("was suppose to serve HTTP/2.0 and %s come", b)
+	}
+	if cn := resp.TLS.PeerCertificates[0].Subject.CommonName; cn != "first" {
+		t.Errorf("was suppose to serve certificate first and %s come", cn)
+	}
+
+	t.Run("reload_certificate", func(t *testing.T) {
+		// make sure the new files get a different mod time
+		time.Sleep(20 * time.Millisecond)
+		writeSelfSignedCert(t, certFile, keyFile, "second")
+
+		resp, err := client.Get("https://" + addr + "/v1/user")
+		if err != nil {
+			t.Fatalf("error: %v", err)
+		}
+		resp.Body.Close()
+		if cn := resp.TLS.PeerCertificates[0].Subject.CommonName; cn != "second" {
+			t.Errorf("was suppose to serve certificate second and %s come", cn)
+		}
+	})
+
+	t.Run("redirect_http", func(t *testing.T) {
+		resp, err := client.Get("http://" + redirectAddr + "/v1/user?id=1")
+		if err != nil {
+			t.Fatalf("error: %v", err)
+		}
+		resp.Body.Close()
+
+		_, port, _ := net.SplitHostPort(addr)
+		want := "https://127.0.0.1:" + port + "/v1/user?id=1"
+		if resp.StatusCode != http.StatusPermanentRedirect || resp.Header.Get("Location") != want {
+			t.Errorf("was suppose to redirect 308 to %s and %d %s come", want, resp.StatusCode, resp.Header.Get("Location"))
+		}
+	})
+
+	q.Shutdown(context.Background())
+	<-errc
+	if _, err := client.Get("http://" + redirectAddr + "/"); err == nil {
+		t.Errorf("redirect server should be closed by Shutdown")
+	}
+}
+
+func TestQuick_ListenTLSMissingCert(t *testing.T) {
+	q := New()
+	if err := q.ListenTLS(freeAddr(t), "missing.pem", "missing.key"); err == nil {
+		t.Errorf("ListenTLS() with missing files should fail")
+	}
+}
@@ -0,0 +1,417 @@
+package quick
+
+import (
+	"errors"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/jeffotoni/quick/internal/concat"
+)
+
+// ValidatorFunc reports whether field passes a validate rule. param is
+// the text after "=" in the rule, e.g. "3" for min=3.
+type ValidatorFunc func(field reflect.Value, param string) bool
+
+// RegisterValidator adds a rule for validate tags, e.g. a "cpf" rule used
+// as `validate:"required,cpf"`. It replaces a built-in rule of the same
+// name and must be called before the server starts.
+func (q *Quick) RegisterValidator(name string, fn ValidatorFunc) {
+	if q.validators == nil {
+		q.validators = make(map[string]ValidatorFunc)
+	}
+	q.validators[name] = fn
+	// a rule unknown so far may be known now
+	q.rules = new(sync.Map)
+}
+
+// Validate checks v against its validate tags. Bind, BodyParser and
+// BindAll call it after filling v. Failures are answered with 422 and a
+// FieldError for each field.
+//
+// Built-in rules: required, omitempty, min, max, len, oneof, email, url,
+// uuid, alpha, alphanum and numeric. min, max and len count characters
+// for strings, items for slices and maps and compare numbers by value.
+// Nested structs and slices of structs are validated too. A rule that is
+// neither built in nor registered fails with 500.
+func (c *Ctx) Validate(v any) error {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var validators map[string]ValidatorFunc
+	if c.quick != nil {
+		validators = c.quick.validators
+	}
+	if err := c.quick.checkRules(rv.Type(), validators); err != nil {
+		return err
+	}
+
+	var errs BindErrors
+	if err := validateStruct(rv, "", validators, &errs); err != nil {
+		return err
+	}
+	if len(errs) > 0 {
+		return ErrUnprocessableEntity.Wrap(errs).WithDetails(errs)
+	}
+	return nil
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// checkRules makes sure every rule in the validate tags of rt and of the
+// structs it nests is known. The result is kept per type, so a typo in a
+// tag costs one walk and not one per request.
+func (q *Quick) checkRules(rt reflect.Type, validators map[string]ValidatorFunc) error {
+	if q == nil || q.rules == nil {
+		return ruleError(rt, "", validators, map[reflect.Type]bool{})
+	}
+	if err, ok := q.rules.Load(rt); ok {
+		err, _ := err.(error)
+		return err
+	}
+	err := ruleError(rt, "", validators, map[reflect.Type]bool{})
+	q.rules.Store(rt, err)
+	return err
+}
+
+func ruleError(rt reflect.Type, prefix string, validators map[string]ValidatorFunc, seen map[reflect.Type]bool) error {
+	for rt.Kind() == reflect.Ptr || rt.Kind() == reflect.Slice || rt.Kind() == reflect.Array {
+		rt = rt.Elem()
+	}
+	if rt.Kind() != reflect.Struct || rt == timeType || seen[rt] {
+		return nil
+	}
+	seen[rt] = true
+
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		tag := field.Tag.Get("validate")
+		if tag == "-" || (!field.IsExported() && !field.Anonymous) {
+			continue
+		}
+		if field.Anonymous && len(tag) == 0 {
+			if err := ruleError(field.Type, prefix, validators, seen); err != nil {
+				return err
+			}
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+
+		path := concat.String(prefix, field.Name)
+		for _, rule := range strings.Split(tag, ",") {
+			name, _, _ := strings.Cut(strings.TrimSpace(rule), "=")
+			if !knownRule(name, validators) {
+				return unknownRule(name, path)
+			}
+		}
+		if err := ruleError(field.Type, concat.String(path, "."), validators, seen); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func knownRule(name string, validators map[string]ValidatorFunc) bool {
+	if len(name) == 0 || name == "omitempty" || name == "required" {
+		return true
+	}
+	_, custom := validators[name]
+	_, builtin := builtinValidators[name]
+	return custom || builtin
+}
+
+// unknownRule is a mistake in the code, not in the request.
+func unknownRule(name, path string) error {
+	return ErrInternalServerError.Wrap(errors.New(concat.String("quick: unknown validate rule ", strconv.Quote(name), " on ", path)))
+}
+
+func validateStruct(rv reflect.Value, prefix string, validators map[string]ValidatorFunc, errs *BindErrors) error {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		tag := field.Tag.Get("validate")
+		if tag == "-" || (!field.IsExported() && !field.Anonymous) {
+			continue
+		}
+
+		fv := rv.Field(i)
+		if field.Anonymous && len(tag) == 0 {
+			for fv.Kind() == reflect.Ptr && !fv.IsNil() {
+				fv = fv.Elem()
+			}
+			if fv.Kind() == reflect.Struct {
+				if err := validateStruct(fv, prefix, validators, errs); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+
+		path := concat.String(prefix, field.Name)
+		if len(tag) > 0 {
+			ok, err := validateField(fv, field, path, tag, validators, errs)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				continue
+			}
+		}
+		if err := validateNested(fv, path, validators, errs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateNested walks into struct fields and slices of structs.
+func validateNested(fv reflect.Value, path string, validators map[string]ValidatorFunc, errs *BindErrors) error {
+	for fv.Kind() == reflect.Ptr || fv.Kind() == reflect.Interface {
+		if fv.IsNil() {
+			return nil
+		}
+		fv = fv.Elem()
+	}
+
+	switch {
+	case fv.Kind() == reflect.Struct && fv.Type() != timeType:
+		return validateStruct(fv, concat.String(path, "."), validators, errs)
+	case fv.Kind() == reflect.Slice || fv.Kind() == reflect.Array:
+		for i := 0; i < fv.Len(); i++ {
+			if err := validateNested(fv.Index(i), concat.String(path, "[", strconv.Itoa(i), "]"), validators, errs); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// validateField runs the rules of tag on fv and reports whether they all
+// passed, so nested values of a failed field are not checked again.
+func validateField(fv reflect.Value, field reflect.StructField, path, tag string, validators map[string]ValidatorFunc, errs *BindErrors) (bool, error) {
+	rules := strings.Split(tag, ",")
+	for _, rule := range rules {
+		if rule == "omitempty" && isEmptyValue(fv) {
+			return true, nil
+		}
+	}
+
+	// rules other than required apply to the value behind a pointer
+	value := fv
+	for value.Kind() == reflect.Ptr && !value.IsNil() {
+		value = value.Elem()
+	}
+
+	for _, rule := range rules {
+		name, param, _ := strings.Cut(strings.TrimSpace(rule), "=")
+		if len(name) == 0 || name == "omitempty" {
+			continue
+		}
+
+		var ok bool
+		if fn, custom := validators[name]; custom {
+			ok = fn(value, param)
+		} else if name == "required" {
+			ok = !isEmptyValue(fv)
+		} else if value.Kind() == reflect.Ptr {
+			// nil optional field, only required applies
+			ok = true
+		} else if check, builtin := builtinValidators[name]; builtin {
+			ok = check(value, param)
+		} else {
+			// only reached through interface fields checkRules can not see into
+			return false, unknownRule(name, path)
+		}
+
+		if !ok {
+			*errs = append(*errs, &FieldError{
+				Field:   path,
+				Source:  "validate",
+				Key:     fieldKey(field),
+				Value:   valueString(value),
+				Message: validateMessage(name, param, value),
+				Rule:    strings.TrimSpace(rule),
+			})
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// fieldKey is the name the client knows the field by.
+func fieldKey(field reflect.StructField) string {
+	for _, tag := range []string{"json", "xml", "form", "query", "param", "header"} {
+		if name, _, _ := strings.Cut(field.Tag.Get(tag), ","); len(name) > 0 && name != "-" {
+			return name
+		}
+	}
+	return field.Name
+}
+
+func valueString(v reflect.Value) string {
+	switch v.Kind() {
+	case reflect.String:
+		return v.String()
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64)
+	}
+	return ""
+}
+
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Invalid:
+		return true
+	case reflect.Ptr, reflect.Interface:
+		return v.IsNil()
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+		return v.Len() == 0
+	}
+	return v.IsZero()
+}
+
+var builtinValidators = map[string]ValidatorFunc{
+	"min": func(v reflect.Value, param string) bool {
+		size, limit, ok := sizeAndLimit(v, param)
+		return ok && size >= limit
+	},
+	"max": func(v reflect.Value, param string) bool {
+		size, limit, ok := sizeAndLimit(v, param)
+		return ok && size <= limit
+	},
+	"len": func(v reflect.Value, param string) bool {
+		size, limit, ok := sizeAndLimit(v, param)
+		return ok && size == limit
+	},
+	"oneof": func(v reflect.Value, param string) bool {
+		s := valueString(v)
+		for _, option := range strings.Fields(param) {
+			if s == option {
+				return true
+			}
+		}
+		return false
+	},
+	"email": func(v reflect.Value, _ string) bool {
+		addr, err := mail.ParseAddress(v.String())
+		return v.Kind() == reflect.String && err == nil && addr.Address == v.String()
+	},
+	"url": func(v reflect.Value, _ string) bool {
+		u, err := url.ParseRequestURI(v.String())
+		return v.Kind() == reflect.String && err == nil && len(u.Scheme) > 0 && len(u.Host) > 0
+	},
+	"uuid": func(v reflect.Value, _ string) bool {
+		return v.Kind() == reflect.String && uuidRegex.MatchString(v.String())
+	},
+	"alpha": func(v reflect.Value, _ string) bool {
+		return v.Kind() == reflect.String && allRunes(v.String(), unicode.IsLetter)
+	},
+	"alphanum": func(v reflect.Value, _ string) bool {
+		return v.Kind() == reflect.String && allRunes(v.String(), func(r rune) bool {
+			return unicode.IsLetter(r) || unicode.IsDigit(r)
+		})
+	},
+	"numeric": func(v reflect.Value, _ string) bool {
+		_, err := strconv.ParseFloat(v.String(), 64)
+		return v.Kind() == reflect.String && err == nil
+	},
+}
+
+var uuidRegex = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+func allRunes(s string, fn func(rune) bool) bool {
+	if len(s) == 0 {
+		return false
+	}
+	for _, r := range s {
+		if !fn(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// sizeAndLimit returns what min, max and len compare: characters of a
+// string, items of a slice or map, or the number itself.
+func sizeAndLimit(v reflect.Value, param string) (size, limit float64, ok bool) {
+	limit, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return float64(utf8.RuneCountInString(v.String())), limit, true
+	case reflect.Slice, reflect.Map, reflect.Array:
+		return float64(v.Len()), limit, true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), limit, true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), limit, true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), limit, true
+	}
+	return 0, 0, false
+}
+
+func validateMessage(rule, param string, v reflect.Value) string {
+	unit := ""
+	switch v.Kind() {
+	case reflect.String:
+		unit = " characters"
+	case reflect.Slice, reflect.Map, reflect.Array:
+		unit = " items"
+	}
+
+	switch rule {
+	case "required":
+		return "is required"
+	case "min":
+		return concat.String("must be at least ", param, unit)
+	case "max":
+		return concat.String("must be at most ", param, unit)
+	case "len":
+		return concat.String("must be exactly ", param, unit)
+	case "oneof":
+		return concat.String("must be one of ", strings.Join(strings.Fields(param), ", "))
+	case "email":
+		return "must be a valid email address"
+	case "url":
+		return "must be a valid URL"
+	case "uuid":
+		return "must be a valid UUID"
+	case "alpha":
+		return "must contain only letters"
+	case "alphanum":
+		return "must contain only letters and digits"
+	case "numeric":
+		return "must be a number"
+	}
+	return concat.String("failed the ", rule, " rule")
+}
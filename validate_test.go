@@ -0,0 +1,195 @@
+package quick
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type validateAddress struct {
+	City string `json:"city" validate:"required"`
+	Zip  string `json:"zip" validate:"len=8,numeric"`
+}
+
+type validateItem struct {
+	SKU string `json:"sku" validate:"alphanum"`
+	Qty int    `json:"qty" validate:"min=1,max=10"`
+}
+
+type validateRequest struct {
+	Name    string          `json:"name" validate:"required,min=3,max=20"`
+	Email   string          `json:"email" validate:"required,email"`
+	Site    string          `json:"site" validate:"omitempty,url"`
+	Role    string          `json:"role" validate:"oneof=admin user"`
+	ID      string          `json:"id" validate:"omitempty,uuid"`
+	Nick    *string         `json:"nick" validate:"omitempty,alpha"`
+	Doc     string          `json:"doc" validate:"omitempty,even"`
+	Address validateAddress `json:"address"`
+	Items   []validateItem  `json:"items" validate:"min=1"`
+}
+
+// cover     -> go test -v -count=1 -cover -failfast -run ^TestCtx_Validate$
+// coverHTML -> go test -v -count=1 -failfast -cover -coverprofile=coverage.out -run ^TestCtx_Validate$; go tool cover -html=coverage.out
+func TestCtx_Validate(t *testing.T) {
+	q := New()
+	q.RegisterValidator("even", func(field reflect.Value, _ string) bool {
+		return field.Len()%2 == 0
+	})
+	q.Post("/v1/user", func(c *Ctx) error {
+		var req validateRequest
+		if err := c.Bind(&req); err != nil {
+			return err
+		}
+		return c.Status(201).JSON(req)
+	})
+
+	headers := map[string]string{"Content-Type": "application/json", "Accept": "application/json"}
+
+	t.Run("valid", func(t *testing.T) {
+		body := `{"name":"jeff","email":"jeff@example.com","site":"https://example.com","role":"admin",
+			"id":"123e4567-e89b-12d3-a456-426614174000","nick":"jo","doc":"12",
+			"address":{"city":"Floripa","zip":"88000000"},"items":[{"sku":"A1","qty":2}]}`
+		data, _ := q.QuickTest("POST", "/v1/user", headers, []byte(body))
+		if data.StatusCode() != 201 {
+			t.Errorf("was suppose to return 201 and %d %s come", data.StatusCode(), data.BodyStr())
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		body := `{"name":"jo","email":"jeff","site":"example","role":"root","id":"123","nick":"j0","doc":"123",
+			"address":{"zip":"88a"},"items":[{"sku":"A-1","qty":0}]}`
+		data, _ := q.QuickTest("POST", "/v1/user", headers, []byte(body))
+		if data.StatusCode() != 422 {
+			t.Fatalf("was suppose to return 422 and %d come", data.StatusCode())
+		}
+
+		var res struct {
+			Details []FieldError `json:"details"`
+		}
+		if err := json.Unmarshal(data.Body(), &res); err != nil {
+			t.Fatalf("error: %v", err)
+		}
+		want := []FieldError{
+			{Field: "Name", Source: "validate", Key: "name", Value: "jo", Message: "must be at least 3 characters", Rule: "min=3"},
+			{Field: "Email", Source: "validate", Key: "email", Value: "jeff", Message: "must be a valid email address", Rule: "email"},
+			{Field: "Site", Source: "validate", Key: "site", Value: "example", Message: "must be a valid URL", Rule: "url"},
+			{Field: "Role", Source: "validate", Key: "role", Value: "root", Message: "must be one of admin, user", Rule: "oneof=admin user"},
+			{Field: "ID", Source: "validate", Key: "id", Value: "123", Message: "must be a valid UUID", Rule: "uuid"},
+			{Field: "Nick", Source: "validate", Key: "nick", Value: "j0", Message: "must contain only letters", Rule: "alpha"},
+			{Field: "Doc", Source: "validate", Key: "doc", Value: "123", Message: "failed the even rule", Rule: "even"},
+			{Field: "Address.City", Source: "validate", Key: "city", Message: "is required", Rule: "required"},
+			{Field: "Address.Zip", Source: "validate", Key: "zip", Value: "88a", Message: "must be exactly 8 characters", Rule: "len=8"},
+			{Field: "Items[0].SKU", Source: "validate", Key: "sku", Value: "A-1", Message: "must contain only letters and digits", Rule: "alphanum"},
+			{Field: "Items[0].Qty", Source: "validate", Key: "qty", Value: "0", Message: "must be at least 1", Rule: "min=1"},
+		}
+		if len(res.Details) != len(want) {
+			t.Fatalf("was suppose to list %d fields and %+v come", len(want), res.Details)
+		}
+		for i := range want {
+			if res.Details[i] != want[i] {
+				t.Errorf("was suppose to report %+v and %+v come", want[i], res.Details[i])
+			}
+		}
+	})
+
+	t.Run("default_accept", func(t *testing.T) {
+		body := []byte(`{"name":"jeff","email":"jeff","site":"https://example.com","role":"admin",
+			"address":{"city":"Floripa","zip":"88000000"},"items":[{"sku":"A1","qty":2}]}`)
+		for _, accept := range []string{"", "*/*"} {
+			data, _ := q.QuickTest("POST", "/v1/user", map[string]string{"Content-Type": "application/json", "Accept": accept}, body)
+			want := `{"code":422,"message":"Unprocessable Entity","details":[{"field":"Email","source":"validate","key":"email","value":"jeff","message":"must be a valid email address","rule":"email"}]}`
+			if data.StatusCode() != 422 || data.BodyStr() != want {
+				t.Errorf("Accept %q: was suppose to return 422 %s and %d %s come", accept, want, data.StatusCode(), data.BodyStr())
+			}
+			if ct := data.Response().Header.Get("Content-Type"); ct != ContentTypeAppJSON {
+				t.Errorf("Accept %q: was suppose to return Content-Type %s and %s come", accept, ContentTypeAppJSON, ct)
+			}
+		}
+	})
+
+	t.Run("conversion_error", func(t *testing.T) {
+		q := New()
+		q.Get("/v1/page", func(c *Ctx) error {
+			var page struct {
+				Page int `query:"page" validate:"min=1"`
+			}
+			return c.BindAll(&page)
+		})
+
+		data, _ := q.QuickTest("GET", "/v1/page?page=x", nil)
+		if data.StatusCode() != 400 {
+			t.Errorf("was suppose to return 400 and %d come", data.StatusCode())
+		}
+		data, _ = q.QuickTest("GET", "/v1/page?page=0", nil)
+		if data.StatusCode() != 422 {
+			t.Errorf("was suppose to return 422 and %d come", data.StatusCode())
+		}
+	})
+}
+
+func TestCtx_ValidateErrors(t *testing.T) {
+	c := &Ctx{}
+
+	err := c.Validate(&validateAddress{Zip: "12345678"})
+	var errs BindErrors
+	if !errors.As(err, &errs) || !errors.Is(err, ErrUnprocessableEntity) || len(errs) != 1 {
+		t.Fatalf("was suppose to return one BindErrors wrapped in ErrUnprocessableEntity and %v come", err)
+	}
+
+	if err := c.Validate(validateAddress{City: "Floripa", Zip: "12345678"}); err != nil {
+		t.Errorf("was suppose to return nil and %v come", err)
+	}
+	if err := c.Validate(nil); err != nil {
+		t.Errorf("was suppose to ignore nil and %v come", err)
+	}
+
+	err = c.Validate(&struct {
+		Name string `validate:"nope"`
+	}{})
+	if !errors.Is(err, ErrInternalServerError) || !strings.Contains(err.Error(), `"nope" on Name`) {
+		t.Errorf("was suppose to return ErrInternalServerError for an unknown rule and %v come", err)
+	}
+
+	// the rule is unknown even when the nested struct is empty
+	err = c.Validate(&struct {
+		Items []validateAddress
+		Extra *struct {
+			Code string `validate:"omitempty,nope"`
+		}
+	}{})
+	if !errors.Is(err, ErrInternalServerError) || !strings.Contains(err.Error(), "Extra.Code") {
+		t.Errorf("was suppose to return ErrInternalServerError for a nested unknown rule and %v come", err)
+	}
+}
+
+func TestQuick_ValidateUnknownRule(t *testing.T) {
+	type user struct {
+		Name string `json:"name" validate:"required,cpf"`
+	}
+
+	q := New()
+	q.Post("/v1/user", func(c *Ctx) error {
+		var u user
+		if err := c.Bind(&u); err != nil {
+			return err
+		}
+		return c.SendString(u.Name)
+	})
+
+	for i := 0; i < 2; i++ {
+		data, _ := q.QuickTest("POST", "/v1/user", map[string]string{"Content-Type": "application/json"}, []byte(`{"name":"jeff"}`))
+		if data.StatusCode() != 500 {
+			t.Errorf("was suppose to return 500 and %d come", data.StatusCode())
+		}
+	}
+
+	q.RegisterValidator("cpf", func(field reflect.Value, _ string) bool {
+		return field.String() == "jeff"
+	})
+	data, _ := q.QuickTest("POST", "/v1/user", map[string]string{"Content-Type": "application/json"}, []byte(`{"name":"jeff"}`))
+	if data.StatusCode() != 200 || data.BodyStr() != "jeff" {
+		t.Errorf("was suppose to return 200 jeff and %d %s come", data.StatusCode(), data.BodyStr())
+	}
+}